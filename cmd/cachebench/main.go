@@ -0,0 +1,185 @@
+// Command cachebench compares embedding configurations for the semantic cache.
+//
+// It reads labelled prompt pairs (JSONL: {"query": "...", "cached": "...", "match": true})
+// and reports, per configuration and similarity threshold, the precision and recall a
+// cache lookup would have. Scores are plain cosine similarity, as used by the Qdrant collection.
+//
+//	go run ./cmd/cachebench -pairs pairs.jsonl
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"sentinel-core/internal/adapter/client"
+	"sentinel-core/internal/domain/entity"
+
+	"github.com/joho/godotenv"
+	"google.golang.org/genai"
+)
+
+type labelledPair struct {
+	Query  string `json:"query"`
+	Cached string `json:"cached"`
+	Match  bool   `json:"match"`
+}
+
+// benchConfig mirrors an EMBEDDING_MODE: how lookups and stored entries are embedded
+type benchConfig struct {
+	name       string
+	lookupTask entity.EmbeddingTask
+	storedTask entity.EmbeddingTask
+}
+
+var configs = []benchConfig{
+	{"query", entity.TaskRetrievalQuery, entity.TaskRetrievalQuery},
+	{"document", entity.TaskRetrievalQuery, entity.TaskRetrievalDocument},
+	{"similarity", entity.TaskSemanticSimilarity, entity.TaskSemanticSimilarity},
+}
+
+func main() {
+	pairsPath := flag.String("pairs", "", "JSONL file of labelled prompt pairs")
+	thresholdList := flag.String("thresholds", "0.75,0.85,0.90,0.95,0.98", "comma-separated similarity thresholds")
+	model := flag.String("model", "text-embedding-004", "embedding model")
+	flag.Parse()
+
+	if *pairsPath == "" {
+		log.Fatal("-pairs is required")
+	}
+	_ = godotenv.Load(".env.dev")
+	ctx := context.Background()
+
+	pairs, err := loadPairs(*pairsPath)
+	if err != nil {
+		log.Fatalf("failed to read pairs: %v", err)
+	}
+	thresholds, err := parseThresholds(*thresholdList)
+	if err != nil {
+		log.Fatalf("invalid -thresholds: %v", err)
+	}
+
+	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
+		Project:  os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		Location: os.Getenv("GOOGLE_CLOUD_LOCATION"),
+		Backend:  genai.BackendVertexAI,
+	})
+	if err != nil {
+		log.Fatalf("failed to init genai client: %v", err)
+	}
+	embedder := client.NewEmbedderFromClient(genaiClient, *model)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODE\tTHRESHOLD\tPRECISION\tRECALL\tF1")
+	for _, cfg := range configs {
+		scores, err := scorePairs(ctx, embedder, cfg, pairs)
+		if err != nil {
+			log.Fatalf("[%s] embedding failed: %v", cfg.name, err)
+		}
+		for _, t := range thresholds {
+			p, r := precisionRecall(scores, pairs, t)
+			fmt.Fprintf(w, "%s\t%.2f\t%.3f\t%.3f\t%.3f\n", cfg.name, t, p, r, f1(p, r))
+		}
+	}
+	w.Flush()
+}
+
+func scorePairs(ctx context.Context, e *client.Embedder, cfg benchConfig, pairs []labelledPair) ([]float64, error) {
+	scores := make([]float64, len(pairs))
+	for i, p := range pairs {
+		q, err := e.CreateEmbeddingFor(ctx, p.Query, cfg.lookupTask)
+		if err != nil {
+			return nil, err
+		}
+		c, err := e.CreateEmbeddingFor(ctx, p.Cached, cfg.storedTask)
+		if err != nil {
+			return nil, err
+		}
+		scores[i] = cosine(q, c)
+	}
+	return scores, nil
+}
+
+// precisionRecall treats "score >= threshold" as a predicted cache hit
+func precisionRecall(scores []float64, pairs []labelledPair, threshold float64) (float64, float64) {
+	var tp, fp, fn float64
+	for i, p := range pairs {
+		hit := scores[i] >= threshold
+		switch {
+		case hit && p.Match:
+			tp++
+		case hit && !p.Match:
+			fp++
+		case !hit && p.Match:
+			fn++
+		}
+	}
+	return ratio(tp, tp+fp), ratio(tp, tp+fn)
+}
+
+func f1(p, r float64) float64 {
+	return ratio(2*p*r, p+r)
+}
+
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func loadPairs(path string) ([]labelledPair, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pairs []labelledPair
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var p labelledPair
+		if err := json.Unmarshal([]byte(text), &p); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		pairs = append(pairs, p)
+	}
+	return pairs, scanner.Err()
+}
+
+func parseThresholds(list string) ([]float64, error) {
+	var out []float64
+	for _, part := range strings.Split(list, ",") {
+		t, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
@@ -0,0 +1,207 @@
+// Command cachectl imports and exports semantic cache entries through the admin API.
+//
+// Import sends a JSONL file ({"prompt": "...", "content": "...", "metadata": {...}, "vector": [...]})
+// in chunks and reports progress; entries without a usable vector are re-embedded by the gateway.
+// Entries are cached for -tenant (default) and -user; without -user they are shared tenant-wide,
+// which is only served when the tenant's cache_scope is "tenant".
+// Export streams every matching entry to a JSONL file.
+//
+//	go run ./cmd/cachectl import -file curated.jsonl -tenant bank-klang
+//	go run ./cmd/cachectl export -user alice -out alice.jsonl
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"sentinel-core/internal/domain/entity"
+
+	"github.com/joho/godotenv"
+)
+
+type client struct {
+	baseURL string
+	token   string
+}
+
+func main() {
+	_ = godotenv.Load(".env.dev")
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	baseURL := fs.String("url", "http://localhost:"+os.Getenv("PORT"), "gateway base URL")
+	token := fs.String("token", os.Getenv("ADMIN_API_TOKEN"), "admin API token")
+	file := fs.String("file", "", "import: JSONL file to import")
+	chunk := fs.Int("chunk", 500, "import: lines sent per request")
+	batch := fs.Int("batch", 0, "import: entries per vector store upsert (gateway default when 0)")
+	out := fs.String("out", "", "export: output file (stdout when empty)")
+	tenant := fs.String("tenant", "", "import: tenant the entries are cached for (default tenant when empty)")
+	user := fs.String("user", "", "import: user the entries are cached for (tenant-wide when empty); export: only entries cached for this user")
+	text := fs.String("text", "", "export: only entries whose prompt matches this text")
+	_ = fs.Parse(os.Args[2:])
+
+	c := client{baseURL: strings.TrimRight(*baseURL, "/"), token: *token}
+	switch os.Args[1] {
+	case "import":
+		if *file == "" {
+			log.Fatal("-file is required")
+		}
+		c.importFile(*file, entity.ImportTarget{TenantID: *tenant, UserID: *user}, *chunk, *batch)
+	case "export":
+		c.export(*out, *user, *text)
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cachectl import -file <jsonl> [-tenant <id>] [-user <id>] | cachectl export [-user <id>] [-out <file>]")
+	os.Exit(2)
+}
+
+// importFile posts the file chunk by chunk so large sets stay under the gateway's body limit
+func (c client) importFile(path string, target entity.ImportTarget, chunkLines, batch int) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var (
+		total     entity.ImportSummary
+		body      bytes.Buffer
+		lines     int
+		firstLine = 1
+	)
+	send := func() {
+		if lines == 0 {
+			return
+		}
+		summary, err := c.postChunk(body.Bytes(), target, batch)
+		total.Read += summary.Read
+		total.Imported += summary.Imported
+		total.Reembedded += summary.Reembedded
+		total.Failed += summary.Failed
+		for _, e := range summary.Errors {
+			fmt.Fprintf(os.Stderr, "  chunk from line %d, %s\n", firstLine, e)
+		}
+		fmt.Fprintf(os.Stderr, "%d read, %d imported, %d re-embedded, %d failed\n", total.Read, total.Imported, total.Reembedded, total.Failed)
+		if err != nil {
+			log.Fatalf("import stopped: %v", err)
+		}
+		firstLine += lines
+		body.Reset()
+		lines = 0
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		body.Write(scanner.Bytes())
+		body.WriteByte('\n')
+		lines++
+		if lines == chunkLines {
+			send()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("failed to read %s: %v", path, err)
+	}
+	send()
+}
+
+func (c client) postChunk(chunk []byte, target entity.ImportTarget, batch int) (entity.ImportSummary, error) {
+	query := url.Values{}
+	if target.TenantID != "" {
+		query.Set("tenant_id", target.TenantID)
+	}
+	if target.UserID != "" {
+		query.Set("user_id", target.UserID)
+	}
+	if batch > 0 {
+		query.Set("batch_size", strconv.Itoa(batch))
+	}
+	endpoint := c.baseURL + "/admin/cache/import?" + query.Encode()
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(chunk))
+	if err != nil {
+		return entity.ImportSummary{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return entity.ImportSummary{}, err
+	}
+	defer resp.Body.Close()
+
+	// Failed imports wrap the partial summary next to the error
+	var result struct {
+		entity.ImportSummary
+		Error   string               `json:"error"`
+		Summary entity.ImportSummary `json:"summary"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return entity.ImportSummary{}, fmt.Errorf("unexpected response (%s): %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return result.Summary, fmt.Errorf("%s: %s", resp.Status, result.Error)
+	}
+	return result.ImportSummary, nil
+}
+
+func (c client) export(path, user, text string) {
+	query := url.Values{}
+	if user != "" {
+		query.Set("user_id", user)
+	}
+	if text != "" {
+		query.Set("text", text)
+	}
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/admin/cache/export?"+query.Encode(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		log.Fatalf("export failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		log.Fatalf("export failed: %s: %s", resp.Status, msg)
+	}
+
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		log.Fatalf("export interrupted after %d bytes: %v", n, err)
+	}
+	fmt.Fprintf(os.Stderr, "exported %d bytes\n", n)
+}
+
+func (c client) do(req *http.Request) (*http.Response, error) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return http.DefaultClient.Do(req)
+}
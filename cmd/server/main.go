@@ -2,17 +2,29 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"sentinel-core/internal/adapter/api"
+	"sentinel-core/internal/adapter/auth"
 	"sentinel-core/internal/adapter/client"
+	"sentinel-core/internal/adapter/guard"
+	"sentinel-core/internal/adapter/kms"
+	"sentinel-core/internal/adapter/pii"
 	"sentinel-core/internal/adapter/store"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
 	"sentinel-core/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/qdrant/go-client/qdrant"
 	"github.com/redis/go-redis/v9"
@@ -26,13 +38,10 @@ func main() {
 	ctx := context.Background()
 
 	redisAddr := os.Getenv("REDIS_ADDR")
-	qdrantHost := os.Getenv("QDRANT_HOST")
-	qdrantPortStr := os.Getenv("QDRANT_PORT")
 	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
 	location := os.Getenv("GOOGLE_CLOUD_LOCATION")
 	tokenLimitStr := os.Getenv("USER_TOKEN_LIMIT")
 
-	qdrantPort, _ := strconv.Atoi(qdrantPortStr)
 	tokenLimit, _ := strconv.Atoi(tokenLimitStr)
 
 	// Redis for Rate Limiting
@@ -40,15 +49,6 @@ func main() {
 		Addr: redisAddr,
 	})
 
-	// Qdrant for Semantic Cache
-	qClient, err := qdrant.NewClient(&qdrant.Config{
-		Host: qdrantHost,
-		Port: qdrantPort,
-	})
-	if err != nil {
-		log.Fatalf("failed to connect to qdrant: %v", err)
-	}
-
 	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
 		Project:  projectID,
 		Location: location,
@@ -58,24 +58,126 @@ func main() {
 		log.Fatalf("failed to init genai client: %v", err)
 	}
 
-	primaryModel := client.NewGeminiClientFromClient(genaiClient, "gemini-2.5-flash")
+	primaryModelName := "gemini-2.5-flash"
+	primaryModel := client.NewGeminiClientFromClient(genaiClient, primaryModelName)
 	fallbackModel := client.NewGeminiClientFromClient(genaiClient, "gemini-2.5-flash-lite")
 
 	resilientProvider := usecase.NewResilientProvider(primaryModel, fallbackModel)
 
-	embedder := client.NewEmbedderFromClient(genaiClient, "text-embedding-004")
+	embeddingModelName := envString("EMBEDDING_MODEL", "text-embedding-004")
+	embedder := client.NewEmbedderFromClient(genaiClient, embeddingModelName)
 	evaluator := client.NewGeminiEvaluator(genaiClient, "gemini-2.5-flash")
 	extractor := client.NewGeminiExtractor(genaiClient, "gemini-2.5-flash")
 
-	vectorStore := store.NewQdrantStore(qClient, os.Getenv("QDRANT_COLLECTION"))
-	if err := vectorStore.InitCollection(ctx, 768); err != nil {
-		log.Fatalf("failed to init qdrant collection: %v", err)
+	// Semantic Cache backend (Qdrant by default)
+	embeddingMode := entity.ParseEmbeddingMode(os.Getenv("EMBEDDING_MODE"))
+	payloadCipher := setupPayloadCipher(rdb)
+	vectorStore, startMigration := setupVectorStore(ctx, embedder, embeddingMode, embeddingModelName, payloadCipher)
+
+	// Entries sealed with retired data keys (or cached in plaintext) are re-encrypted in the background
+	var reencryptor *usecase.Reencryptor
+	if target, ok := vectorStore.(repository.Reencrypter); ok && payloadCipher != nil {
+		reencryptor = usecase.NewReencryptor(target)
+		reencryptor.Start(ctx, envDuration("ENCRYPTION_REENCRYPT_INTERVAL", time.Hour))
 	}
 
-	tokenLimiter := store.NewRedisLimiter(rdb, tokenLimit)
+	// Token budgets: USER_TOKEN_LIMIT by default, per rate tier for API keys ("free=10000,pro=500000")
+	rateTiers := entity.ParseQuotaOverrides(os.Getenv("RATE_TIERS"))
+	tokenLimiter := store.NewRedisLimiter(rdb, tokenLimit).WithTiers(rateTiers)
+	feedbackStore := store.NewRedisFeedbackStore(rdb, 100000)
+
+	// Cached answers are tied to the model and prompt template that produced them
+	profile := entity.GenerationProfile{
+		Provider:        "gemini",
+		Model:           primaryModelName,
+		TemplateVersion: envString("PROMPT_TEMPLATE_VERSION", "v1"),
+	}
+	cacheAdmin := usecase.NewCacheAdmin(vectorStore)
+	if os.Getenv("CACHE_PURGE_OLD_TEMPLATES") == "true" {
+		go func() {
+			// Opt-in bulk invalidation of answers from other template versions; other versions are
+			// never served either way, this only reclaims their space
+			if err := cacheAdmin.InvalidateTemplateVersions(context.Background(), profile.TemplateVersion); err != nil {
+				log.Printf("[SENTINEL] Template version invalidation failed: %v", err)
+			}
+		}()
+	}
+
+	// Per-user and per-tenant quotas, enforced by periodic compaction
+	compactor := usecase.NewCacheCompactor(vectorStore,
+		entity.CacheQuota{
+			Default:         envInt("CACHE_QUOTA_PER_USER", 0),
+			Overrides:       entity.ParseQuotaOverrides(os.Getenv("CACHE_QUOTA_OVERRIDES")),
+			TenantDefault:   envInt("CACHE_QUOTA_PER_TENANT", 0),
+			TenantOverrides: entity.ParseQuotaOverrides(os.Getenv("CACHE_QUOTA_TENANT_OVERRIDES")),
+		},
+		entity.ParseEvictionPolicy(os.Getenv("CACHE_EVICTION_POLICY")),
+	)
+	compactor.Start(ctx, envDuration("CACHE_COMPACTION_INTERVAL", 10*time.Minute))
+
+	// Cache freshness and optional stale-while-revalidate
+	orchOpts := []usecase.Option{
+		usecase.WithCacheCompatibility(profile, entity.ParseCompatibilityPolicy(os.Getenv("CACHE_COMPATIBILITY"))),
+		usecase.WithEmbeddingMode(embeddingMode),
+		usecase.WithFreshness(envDuration("CACHE_FRESHNESS", 24*time.Hour)),
+		usecase.WithCandidateReranking(envInt("CACHE_TOP_K", 5), envInt("CACHE_JUDGE_BUDGET", 3)),
+	}
+	if os.Getenv("PII_REDACTION_ENABLED") != "false" {
+		orchOpts = append(orchOpts, usecase.WithRedaction(setupRedactor()))
+	}
+	if os.Getenv("INJECTION_GUARD_ENABLED") == "true" {
+		classifiers := []repository.InjectionClassifier{guard.NewHeuristicClassifier()}
+		if os.Getenv("INJECTION_MODEL_CLASSIFIER") == "true" {
+			classifiers = append(classifiers, client.NewGeminiInjectionClassifier(genaiClient, "gemini-2.5-flash-lite"))
+		}
+		orchOpts = append(orchOpts, usecase.WithInjectionGuard(usecase.NewInjectionGuard(
+			envFloat("INJECTION_FLAG_THRESHOLD", 0.5),
+			envFloat("INJECTION_BLOCK_THRESHOLD", 0.85),
+			classifiers...,
+		)))
+	}
+	if path := os.Getenv("OUTPUT_POLICY_FILE"); path != "" {
+		orchOpts = append(orchOpts, usecase.WithOutputPolicy(setupOutputPolicy(path)))
+	}
+	models := routableModels(genaiClient, primaryModelName, primaryModel)
+	orchOpts = append(orchOpts, usecase.WithModels(models))
+	var ruleEngine *usecase.RuleEngine
+	if path := os.Getenv("RULES_FILE"); path != "" {
+		ruleEngine, err = usecase.NewRuleEngine(path, slices.Sorted(maps.Keys(models)))
+		if err != nil {
+			log.Fatalf("failed to load rules: %v", err)
+		}
+		ruleEngine.Watch(ctx, envDuration("RULES_RELOAD_INTERVAL", 30*time.Second))
+		orchOpts = append(orchOpts, usecase.WithRules(ruleEngine, setupRedactor()))
+	}
+
+	// Multi-tenancy: per-tenant limits, cache isolation, redaction and fallback chains
+	var tenantService *usecase.TenantService
+	if os.Getenv("TENANTS_ENABLED") == "true" {
+		tenantService = usecase.NewTenantService(store.NewRedisTenantStore(rdb), slices.Sorted(maps.Keys(models)), envDuration("TENANT_CACHE_TTL", 30*time.Second))
+		orchOpts = append(orchOpts,
+			usecase.WithTenants(tenantService),
+			usecase.WithTenantRedaction(usecase.NewRedactor(piiDetectors()...), usecase.NewTokenizer(piiDetectors()...)),
+		)
+	}
+	if sink := setupAuditSink(rdb); sink != nil {
+		auditor, err := usecase.NewAuditor(ctx, sink, os.Getenv("AUDIT_RAW_TEXT") == "true", envInt("AUDIT_BUFFER", 1024))
+		if err != nil {
+			log.Fatalf("failed to init audit log: %v", err)
+		}
+		orchOpts = append(orchOpts, usecase.WithAudit(auditor))
+	}
+	if os.Getenv("CACHE_SWR_ENABLED") == "true" {
+		orchOpts = append(orchOpts, usecase.WithStaleWhileRevalidate(
+			envDuration("CACHE_SWR_MAX_STALENESS", 6*time.Hour),
+			envInt("CACHE_SWR_REFRESH_CONCURRENCY", 4),
+		))
+	}
 
 	// Inject the adapters into the Orchestration Layer
-	orchestrator := usecase.NewOrchestrator(vectorStore, tokenLimiter, resilientProvider, embedder, evaluator, extractor)
+	orchestrator := usecase.NewOrchestrator(vectorStore, tokenLimiter, resilientProvider, embedder, evaluator, extractor, orchOpts...)
+
+	startMigration(orchestrator.Reembed)
 
 	go func() {
 		warmCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -101,9 +203,314 @@ func main() {
 	})
 
 	handler := api.NewPromptHandler(orchestrator)
-	api.SetupRouter(app, handler)
+	feedbackHandler := api.NewFeedbackHandler(usecase.NewFeedbackService(vectorStore, feedbackStore, tenantService))
+	cacheTransfer := usecase.NewCacheTransfer(vectorStore, orchestrator.PrepareImport, embeddingModelName)
+	adminHandler := api.NewAdminHandler(cacheAdmin, cacheTransfer)
+
+	// Authentication (API keys and/or IdP tokens): the credentials, not the body, decide who is asking
+	var keyService *usecase.APIKeyService
+	if os.Getenv("AUTH_API_KEYS") == "true" {
+		keyService = usecase.NewAPIKeyService(setupAPIKeyStore(ctx, rdb), slices.Sorted(maps.Keys(rateTiers)))
+	}
+	tokenVerifier := setupJWTVerifier(ctx, rateTiers)
+	var authenticate fiber.Handler
+	if keyService != nil || tokenVerifier != nil {
+		authenticate = api.Authenticate(keyService, tokenVerifier)
+	}
+	if authenticate == nil && tenantService != nil {
+		log.Printf("[TENANTS] No authentication configured: every request uses the default tenant")
+	}
+	api.SetupRouter(app, handler, feedbackHandler, adminHandler, api.NewRulesHandler(ruleEngine), api.NewKeyHandler(keyService), api.NewTenantHandler(tenantService), api.NewEncryptionHandler(payloadCipher, reencryptor, tenantService), authenticate)
 
 	// Start Server
 	log.Printf("Sentinel-AI Gateway running on port %s", os.Getenv("PORT"))
 	log.Fatal(app.Listen(":" + os.Getenv("PORT")))
 }
+
+// setupVectorStore builds the configured semantic cache backend. The returned func
+// starts a pending collection migration once the orchestrator can re-embed entries.
+func setupVectorStore(ctx context.Context, embedder *client.Embedder, mode entity.EmbeddingMode, embeddingModel string, payloadCipher *usecase.EnvelopeCipher) (repository.VectorStore, func(store.Reembedder)) {
+	noMigration := func(store.Reembedder) {}
+	searchVector := envString("EMBEDDING_SEARCH_VECTOR", entity.DocumentVectorName)
+
+	if payloadCipher != nil && slices.Contains([]string{"memory", "postgres", "redis"}, os.Getenv("VECTOR_STORE")) {
+		log.Fatalf("encryption at rest is only supported by the qdrant store (unset ENCRYPTION_KEY_FILE or VECTOR_STORE)")
+	}
+
+	if os.Getenv("VECTOR_STORE") == "memory" {
+		// In-process cache for tests and single-binary deployments
+		memStore, err := store.NewMemoryStore(os.Getenv("MEMORY_STORE_SNAPSHOT"))
+		if err != nil {
+			log.Fatalf("failed to init memory store: %v", err)
+		}
+		if mode == entity.EmbedModeDual && searchVector == entity.DocumentVectorName {
+			memStore.SearchDocumentVectors()
+		}
+		memStore.StartSnapshots(ctx, envDuration("MEMORY_STORE_SNAPSHOT_INTERVAL", 5*time.Minute))
+		return memStore, noMigration
+	}
+
+	// The table and collection must match whatever the configured embedder produces
+	dim, err := embedder.Dimension(ctx)
+	if err != nil {
+		log.Fatalf("failed to detect embedding dimension: %v", err)
+	}
+	collectionSpec := store.CollectionSpec{Dimension: dim, EmbeddingModel: embeddingModel}
+
+	if os.Getenv("VECTOR_STORE") == "postgres" {
+		// Postgres + pgvector for deployments without Qdrant
+		pool, err := pgxpool.New(ctx, os.Getenv("POSTGRES_DSN"))
+		if err != nil {
+			log.Fatalf("failed to connect to postgres: %v", err)
+		}
+		pgStore := store.NewPostgresStore(pool)
+		if mode == entity.EmbedModeDual && searchVector == entity.DocumentVectorName {
+			pgStore.SearchDocumentVectors()
+		}
+		if err := pgStore.Migrate(ctx, collectionSpec); err != nil {
+			log.Fatalf("failed to migrate postgres schema: %v", err)
+		}
+		return pgStore, noMigration
+	}
+
+	if os.Getenv("VECTOR_STORE") == "redis" {
+		// Redis Stack vector search; search replies are only parsed over RESP2
+		redisVectorClient := redis.NewClient(&redis.Options{
+			Addr:     envString("REDIS_VECTOR_ADDR", os.Getenv("REDIS_ADDR")),
+			Protocol: 2,
+		})
+		redisStore := store.NewRedisVectorStore(redisVectorClient, envString("REDIS_VECTOR_INDEX", "sentinel-cache"), cacheRetention())
+		if mode == entity.EmbedModeDual && searchVector == entity.DocumentVectorName {
+			redisStore.SearchDocumentVectors()
+		}
+		if err := redisStore.InitIndex(ctx, collectionSpec, envString("REDIS_VECTOR_ALGORITHM", "HNSW")); err != nil {
+			log.Fatalf("failed to init redis vector index: %v", err)
+		}
+		return redisStore, noMigration
+	}
+
+	// Qdrant for Semantic Cache
+	qdrantPort, _ := strconv.Atoi(os.Getenv("QDRANT_PORT"))
+	qClient, err := qdrant.NewClient(&qdrant.Config{
+		Host: os.Getenv("QDRANT_HOST"),
+		Port: qdrantPort,
+	})
+	if err != nil {
+		log.Fatalf("failed to connect to qdrant: %v", err)
+	}
+
+	// Embedding layout: the dual mode keeps query and document vectors side by side
+	var qdrantOpts []store.QdrantOption
+	if mode == entity.EmbedModeDual {
+		qdrantOpts = append(qdrantOpts, store.WithDualVectors(searchVector))
+	}
+	if payloadCipher != nil {
+		qdrantOpts = append(qdrantOpts, store.WithPayloadCipher(payloadCipher))
+	}
+
+	vectorStore := store.NewQdrantStore(qClient, os.Getenv("QDRANT_COLLECTION"), qdrantOpts...)
+	err = vectorStore.InitCollection(ctx, collectionSpec)
+	if err == nil {
+		return vectorStore, noMigration
+	}
+	if !errors.Is(err, store.ErrCollectionMismatch) || os.Getenv("QDRANT_AUTO_MIGRATE") != "true" {
+		log.Fatalf("failed to init qdrant collection: %v (set QDRANT_AUTO_MIGRATE=true to migrate)", err)
+	}
+	log.Printf("[SENTINEL] %v; migrating to a new collection", err)
+
+	return vectorStore, func(reembed store.Reembedder) {
+		if os.Getenv("QDRANT_MIGRATE_REEMBED") != "true" {
+			reembed = nil
+		}
+		if err := vectorStore.StartMigration(ctx, collectionSpec, reembed); err != nil {
+			log.Fatalf("failed to start qdrant migration: %v", err)
+		}
+	}
+}
+
+// setupAPIKeyStore keeps API keys in Redis, or in Postgres with API_KEY_STORE=postgres.
+func setupAPIKeyStore(ctx context.Context, rdb *redis.Client) repository.APIKeyStore {
+	if os.Getenv("API_KEY_STORE") != "postgres" {
+		return store.NewRedisAPIKeyStore(rdb)
+	}
+	pool, err := pgxpool.New(ctx, os.Getenv("POSTGRES_DSN"))
+	if err != nil {
+		log.Fatalf("failed to connect to postgres: %v", err)
+	}
+	keyStore := store.NewPostgresAPIKeyStore(pool)
+	if err := keyStore.Migrate(ctx); err != nil {
+		log.Fatalf("failed to migrate API key table: %v", err)
+	}
+	return keyStore
+}
+
+// setupPayloadCipher enables encryption at rest when ENCRYPTION_KEY_FILE names a master
+// key file. Data keys are per tenant, kept wrapped in Redis. It returns nil when off.
+func setupPayloadCipher(rdb *redis.Client) *usecase.EnvelopeCipher {
+	path := os.Getenv("ENCRYPTION_KEY_FILE")
+	if path == "" {
+		return nil
+	}
+	keyring, err := kms.LoadKeyFile(path)
+	if err != nil {
+		log.Fatalf("failed to init encryption at rest: %v", err)
+	}
+	log.Printf("[ENCRYPTION] Sealing cached prompts and answers (master key %s)", keyring.CurrentKeyID())
+	return usecase.NewEnvelopeCipher(keyring, store.NewRedisDataKeyStore(rdb))
+}
+
+// setupAuditSink picks where audit events go: AUDIT_SINK=file (AUDIT_FILE) or redis
+// (AUDIT_REDIS_STREAM, one per instance). It returns nil when auditing is off.
+func setupAuditSink(rdb *redis.Client) repository.AuditSink {
+	switch os.Getenv("AUDIT_SINK") {
+	case "file":
+		sink, err := store.NewFileAuditSink(envString("AUDIT_FILE", "audit.jsonl"), os.Getenv("AUDIT_FILE_SYNC") == "true")
+		if err != nil {
+			log.Fatalf("failed to init audit log: %v", err)
+		}
+		return sink
+	case "redis":
+		host, _ := os.Hostname()
+		return store.NewRedisAuditSink(rdb, envString("AUDIT_REDIS_STREAM", "audit:"+host))
+	}
+	return nil
+}
+
+// setupJWTVerifier accepts IdP tokens when a JWKS is configured, from JWT_JWKS_URL or,
+// for air-gapped setups, JWT_JWKS_FILE. It returns nil when JWT auth is off.
+func setupJWTVerifier(ctx context.Context, rateTiers map[string]int) repository.TokenVerifier {
+	var keys auth.KeySet
+	switch {
+	case os.Getenv("JWT_JWKS_FILE") != "":
+		static, err := auth.LoadJWKSFile(os.Getenv("JWT_JWKS_FILE"))
+		if err != nil {
+			log.Fatalf("failed to load JWKS: %v", err)
+		}
+		keys = static
+	case os.Getenv("JWT_JWKS_URL") != "":
+		remote := auth.NewRemoteJWKS(os.Getenv("JWT_JWKS_URL"))
+		if err := remote.Refresh(ctx); err != nil {
+			// Retried on the first token, so a slow IdP does not keep the gateway down
+			log.Printf("[JWT] Initial JWKS fetch failed: %v", err)
+		}
+		remote.Start(ctx, envDuration("JWT_JWKS_REFRESH_INTERVAL", time.Hour))
+		keys = remote
+	default:
+		return nil
+	}
+
+	verifier, err := auth.NewJWTVerifier(keys, auth.JWTConfig{
+		Issuer:      os.Getenv("JWT_ISSUER"),
+		Audience:    os.Getenv("JWT_AUDIENCE"),
+		Leeway:      envDuration("JWT_LEEWAY", 30*time.Second),
+		UserClaim:   envString("JWT_USER_CLAIM", "sub"),
+		TenantClaim: envString("JWT_TENANT_CLAIM", "tenant_id"),
+		RolesClaim:  envString("JWT_ROLES_CLAIM", "roles"),
+		RateTiers:   rateTiers,
+	})
+	if err != nil {
+		log.Fatalf("failed to init JWT auth: %v", err)
+	}
+	return verifier
+}
+
+// routableModels are the models a rule may force or a tenant fallback chain may name:
+// the primary plus RULES_MODELS. The orchestrator puts retries and fallbacks around them.
+func routableModels(genaiClient *genai.Client, primary string, primaryModel repository.AIProvider) map[string]repository.AIProvider {
+	models := map[string]repository.AIProvider{primary: primaryModel}
+	for _, name := range strings.Split(os.Getenv("RULES_MODELS"), ",") {
+		if name = strings.TrimSpace(name); name != "" && models[name] == nil {
+			models[name] = client.NewGeminiClientFromClient(genaiClient, name)
+		}
+	}
+	return models
+}
+
+// setupRedactor masks prompts with the PII detectors.
+// PII_REDACTION_MODE=tokenize makes redaction reversible for the caller.
+func setupRedactor() *usecase.Redactor {
+	detectors := piiDetectors()
+	if os.Getenv("PII_REDACTION_MODE") == "tokenize" {
+		return usecase.NewTokenizer(detectors...)
+	}
+	return usecase.NewRedactor(detectors...)
+}
+
+// setupOutputPolicy loads the output rules from a JSON file ({"rules": [...]}).
+func setupOutputPolicy(path string) *usecase.OutputPolicy {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("failed to read output policy: %v", err)
+	}
+	var cfg entity.OutputPolicyConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		log.Fatalf("failed to parse output policy: %v", err)
+	}
+	policy, err := usecase.NewOutputPolicy(cfg.Rules, piiDetectors()...)
+	if err != nil {
+		log.Fatalf("invalid output policy: %v", err)
+	}
+	log.Printf("[POLICY] Loaded %d output rules from %s", len(cfg.Rules), path)
+	return policy
+}
+
+// piiDetectors builds the configured built-in detectors plus an optional custom dictionary.
+func piiDetectors() []repository.PIIDetector {
+	var names []string
+	if list := os.Getenv("PII_DETECTORS"); list != "" {
+		names = strings.Split(list, ",")
+	}
+	detectors, err := pii.Detectors(names...)
+	if err != nil {
+		log.Fatalf("failed to init PII detectors: %v", err)
+	}
+	if path := os.Getenv("PII_DICTIONARY_FILE"); path != "" {
+		dictionary, err := pii.LoadDictionary(path)
+		if err != nil {
+			log.Fatalf("failed to load PII dictionary: %v", err)
+		}
+		detectors = append(detectors, dictionary)
+	}
+	return detectors
+}
+
+// cacheRetention is how long an entry can still be served: the freshness window plus
+// the stale-while-revalidate grace period when enabled.
+func cacheRetention() time.Duration {
+	retention := envDuration("CACHE_FRESHNESS", 24*time.Hour)
+	if os.Getenv("CACHE_SWR_ENABLED") == "true" {
+		retention += envDuration("CACHE_SWR_MAX_STALENESS", 6*time.Hour)
+	}
+	return retention
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+func envInt(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func envFloat(key string, def float64) float64 {
+	f, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+func envString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
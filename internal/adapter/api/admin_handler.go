@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/usecase"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminHandler struct {
+	cache    *usecase.CacheAdmin
+	transfer *usecase.CacheTransfer
+}
+
+func NewAdminHandler(cache *usecase.CacheAdmin, transfer *usecase.CacheTransfer) *AdminHandler {
+	return &AdminHandler{cache: cache, transfer: transfer}
+}
+
+// ListCache handles GET /admin/cache?user_id=&text=&meta.<key>=<value>&limit=&cursor=
+func (h *AdminHandler) ListCache(c *fiber.Ctx) error {
+	page, err := h.cache.List(c.Context(), filterFromQuery(c), c.QueryInt("limit"), c.Query("cursor"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+func (h *AdminHandler) GetCacheEntry(c *fiber.Ctx) error {
+	entry, err := h.cache.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(entry)
+}
+
+func (h *AdminHandler) DeleteCacheEntry(c *fiber.Ctx) error {
+	if err := h.cache.Delete(c.Context(), c.Params("id")); err != nil {
+		return adminError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteCacheByFilter handles POST /admin/cache/delete with an entity.CacheFilter body
+func (h *AdminHandler) DeleteCacheByFilter(c *fiber.Ctx) error {
+	var filter entity.CacheFilter
+	if err := c.BodyParser(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := h.cache.DeleteByFilter(c.Context(), filter); err != nil {
+		return adminError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// InvalidateTemplates handles POST /admin/cache/invalidate-templates?keep=<version>, deleting
+// every entry generated under any other prompt-template version. The version to keep is
+// required so a purge is always a deliberate choice.
+func (h *AdminHandler) InvalidateTemplates(c *fiber.Ctx) error {
+	if err := h.cache.InvalidateTemplateVersions(c.Context(), c.Query("keep")); err != nil {
+		return adminError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// PurgeScope handles DELETE /admin/cache/scopes/:user_id?tenant_id=<tenant>, optionally narrowed by
+// meta.<key> query params. The tenant is required; pass "default" for requests that named none.
+func (h *AdminHandler) PurgeScope(c *fiber.Ctx) error {
+	if err := h.cache.PurgeScope(c.Context(), c.Query("tenant_id"), c.Params("user_id"), metaFromQuery(c)); err != nil {
+		return adminError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ImportCache handles POST /admin/cache/import?tenant_id=&user_id=&batch_size= with a JSONL body
+// of entity.CacheExport lines, cached for that tenant (default) and user. Without user_id the
+// entries are shared tenant-wide, served only when the tenant's cache_scope is "tenant".
+// Large files should be sent in chunks (see cmd/cachectl) to stay under the body limit.
+func (h *AdminHandler) ImportCache(c *fiber.Ctx) error {
+	target := entity.ImportTarget{TenantID: c.Query("tenant_id"), UserID: c.Query("user_id")}
+	summary, err := h.transfer.Import(c.Context(), bytes.NewReader(c.Body()), target, c.QueryInt("batch_size"), nil)
+	if errors.Is(err, entity.ErrInvalidRequest) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "summary": summary})
+	}
+	if err != nil {
+		log.Printf("[CACHE-IMPORT] Import aborted: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "import aborted", "summary": summary})
+	}
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+// ExportCache handles GET /admin/cache/export?user_id=&text=&meta.<key>=<value>, streaming JSONL
+func (h *AdminHandler) ExportCache(c *fiber.Ctx) error {
+	filter := filterFromQuery(c)
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="sentinel-cache.jsonl"`)
+
+	// The stream outlives the handler, so it cannot use the request context
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		n, err := h.transfer.Export(context.Background(), w, filter)
+		if err != nil {
+			log.Printf("[CACHE-EXPORT] Export failed after %d entries: %v", n, err)
+		}
+		_ = w.Flush()
+	})
+	return nil
+}
+
+// --- Private Helpers ---
+
+func filterFromQuery(c *fiber.Ctx) entity.CacheFilter {
+	return entity.CacheFilter{
+		UserID:   c.Query("user_id"),
+		Text:     c.Query("text"),
+		Metadata: metaFromQuery(c),
+	}
+}
+
+// metaFromQuery collects "meta.action=transfer" style query params
+func metaFromQuery(c *fiber.Ctx) map[string]string {
+	meta := make(map[string]string)
+	for k, v := range c.Queries() {
+		if key, ok := strings.CutPrefix(k, "meta."); ok && key != "" {
+			meta[key] = v
+		}
+	}
+	return meta
+}
+
+func adminError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, entity.ErrResourceNotFound), errors.Is(err, entity.ErrUnknownTenant):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal gateway error"})
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EncryptionHandler rotates data keys and drives re-encryption; cipher is nil when
+// encryption at rest is off.
+type EncryptionHandler struct {
+	cipher      *usecase.EnvelopeCipher
+	reencryptor *usecase.Reencryptor   // nil when the vector store cannot re-encrypt
+	tenants     *usecase.TenantService // nil when tenants are not configured
+}
+
+func NewEncryptionHandler(cipher *usecase.EnvelopeCipher, reencryptor *usecase.Reencryptor, tenants *usecase.TenantService) *EncryptionHandler {
+	return &EncryptionHandler{cipher: cipher, reencryptor: reencryptor, tenants: tenants}
+}
+
+// RotateDataKey handles POST /admin/encryption/tenants/:id/rotate. New entries are sealed
+// with the new key right away; existing ones are re-encrypted in the background. Keys are
+// only minted for well-formed IDs, and for configured tenants when tenants are enabled.
+func (h *EncryptionHandler) RotateDataKey(c *fiber.Ctx) error {
+	if h.cipher == nil {
+		return encryptionDisabled(c)
+	}
+	id := c.Params("id")
+	if !usecase.ValidTenantID(id) {
+		return adminError(c, fmt.Errorf("%w: tenant id must be 1-64 letters, digits, '-' or '_'", entity.ErrInvalidRequest))
+	}
+	if h.tenants != nil {
+		if _, err := h.tenants.Resolve(c.Context(), id); err != nil {
+			return adminError(c, err)
+		}
+	}
+	key, err := h.cipher.RotateDataKey(c.Context(), id)
+	if err != nil {
+		return adminError(c, err)
+	}
+	if h.reencryptor != nil {
+		h.reencryptor.Trigger()
+	}
+	// The wrapped key itself stays out of responses
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tenant_id":     key.TenantID,
+		"version":       key.Version,
+		"master_key_id": key.MasterKeyID,
+		"created_at":    key.CreatedAt,
+	})
+}
+
+// Reencrypt handles POST /admin/encryption/reencrypt, e.g. after a master key rotation
+func (h *EncryptionHandler) Reencrypt(c *fiber.Ctx) error {
+	if h.cipher == nil || h.reencryptor == nil {
+		return encryptionDisabled(c)
+	}
+	h.reencryptor.Trigger()
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"status": "re-encryption scheduled"})
+}
+
+// --- Private Helpers ---
+
+func encryptionDisabled(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "encryption at rest is disabled (set ENCRYPTION_KEY_FILE)"})
+}
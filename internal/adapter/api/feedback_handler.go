@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeedbackHandler struct {
+	feedback *usecase.FeedbackService
+}
+
+func NewFeedbackHandler(fs *usecase.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{feedback: fs}
+}
+
+func (h *FeedbackHandler) HandleFeedback(c *fiber.Ctx) error {
+	var fb entity.Feedback
+	if err := c.BodyParser(&fb); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if p, ok := principalFrom(c); ok {
+		fb.UserID, fb.TenantID = p.UserID, p.TenantID
+	} else {
+		// Unauthenticated callers could otherwise rate any tenant's cached answers
+		fb.TenantID = ""
+	}
+
+	tally, err := h.feedback.Submit(c.Context(), fb)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidRequest):
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, entity.ErrResourceNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "internal gateway error"})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"response_id": fb.ResponseID,
+		"feedback":    tally,
+	})
+}
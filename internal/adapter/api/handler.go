@@ -4,6 +4,8 @@ import (
 	"errors"
 	"sentinel-core/internal/domain/entity"
 	"sentinel-core/internal/usecase"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -21,6 +23,16 @@ func (h *PromptHandler) HandlePrompt(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "invalid request body"})
 	}
+	applyCacheControlHeader(c.Get(fiber.HeaderCacheControl), &req.Cache)
+	req.Route = c.Route().Path
+	if p, ok := principalFrom(c); ok {
+		// The credentials decide who is asking, never the body
+		req.UserID, req.TenantID = p.UserID, p.TenantID
+		req.RateTier, req.AllowedModels, req.KeyID = p.RateTier, p.AllowedModels, p.KeyID
+	} else {
+		// Unauthenticated callers could otherwise pick any tenant's budget and cache
+		req.TenantID = ""
+	}
 
 	// The Delivery layer maps the business error to HTTP status codes
 	resp, err := h.orchestrator.Execute(c.Context(), req)
@@ -28,6 +40,16 @@ func (h *PromptHandler) HandlePrompt(c *fiber.Ctx) error {
 		if errors.Is(err, entity.ErrRateLimitExceeded) {
 			return c.Status(429).JSON(fiber.Map{"error": err.Error()})
 		}
+		if errors.Is(err, entity.ErrRequestDenied) || errors.Is(err, entity.ErrApprovalRequired) || errors.Is(err, entity.ErrModelNotAllowed) || errors.Is(err, entity.ErrUnknownTenant) {
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, entity.ErrPromptInjection) {
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		}
+		var violation *entity.PolicyError
+		if errors.As(err, &violation) {
+			return c.Status(422).JSON(fiber.Map{"error": err.Error(), "violations": violation.Violations})
+		}
 		return c.Status(500).JSON(fiber.Map{"error": "internal gateway error"})
 	}
 
@@ -36,6 +58,32 @@ func (h *PromptHandler) HandlePrompt(c *fiber.Ctx) error {
 	if resp.Cached {
 		c.Set("X-Sentinel-Cache-Hit", "true")
 	}
+	if resp.Stale {
+		c.Set("X-Sentinel-Cache-Stale", "true")
+	}
+	c.Set("X-Sentinel-Cache-Policy", resp.CachePolicy)
 
 	return c.Status(200).JSON(resp)
 }
+
+// applyCacheControlHeader merges Cache-Control request directives into the JSON ones.
+// Either source can switch a directive on; when both give max-age the stricter wins.
+func applyCacheControlHeader(header string, cc *entity.CacheControl) {
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-cache":
+			cc.NoCache = true
+		case "no-store":
+			cc.NoStore = true
+		case "max-age":
+			age, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil || age < 0 {
+				continue
+			}
+			if cc.MaxAge == nil || age < *cc.MaxAge {
+				cc.MaxAge = &age
+			}
+		}
+	}
+}
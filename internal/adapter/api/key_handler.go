@@ -0,0 +1,97 @@
+package api
+
+import (
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/usecase"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// KeyHandler manages API keys; keys may be nil when API-key authentication is off.
+type KeyHandler struct {
+	keys *usecase.APIKeyService
+}
+
+func NewKeyHandler(keys *usecase.APIKeyService) *KeyHandler {
+	return &KeyHandler{keys: keys}
+}
+
+// CreateKey handles POST /admin/keys with an entity.APIKeySpec body. The secret is only
+// part of this response.
+func (h *KeyHandler) CreateKey(c *fiber.Ctx) error {
+	if h.keys == nil {
+		return keysDisabled(c)
+	}
+	var spec entity.APIKeySpec
+	if err := c.BodyParser(&spec); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	issued, err := h.keys.Create(c.Context(), spec)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(issued)
+}
+
+// ListKeys handles GET /admin/keys?owner=
+func (h *KeyHandler) ListKeys(c *fiber.Ctx) error {
+	if h.keys == nil {
+		return keysDisabled(c)
+	}
+	keys, err := h.keys.List(c.Context(), c.Query("owner"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"keys": keys})
+}
+
+func (h *KeyHandler) GetKey(c *fiber.Ctx) error {
+	if h.keys == nil {
+		return keysDisabled(c)
+	}
+	key, err := h.keys.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(key)
+}
+
+// RotateKey handles POST /admin/keys/:id/rotate?grace=24h; the old secret keeps working
+// for the grace period (none by default).
+func (h *KeyHandler) RotateKey(c *fiber.Ctx) error {
+	if h.keys == nil {
+		return keysDisabled(c)
+	}
+	var grace time.Duration
+	if g := c.Query("grace"); g != "" {
+		d, err := time.ParseDuration(g)
+		if err != nil || d < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid grace duration"})
+		}
+		grace = d
+	}
+	issued, err := h.keys.Rotate(c.Context(), c.Params("id"), grace)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(issued)
+}
+
+// RevokeKey handles DELETE /admin/keys/:id. The record is kept, marked revoked.
+func (h *KeyHandler) RevokeKey(c *fiber.Ctx) error {
+	if h.keys == nil {
+		return keysDisabled(c)
+	}
+	key, err := h.keys.Revoke(c.Context(), c.Params("id"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(key)
+}
+
+// --- Private Helpers ---
+
+func keysDisabled(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "API-key authentication is disabled (set AUTH_API_KEYS)"})
+}
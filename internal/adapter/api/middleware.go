@@ -0,0 +1,75 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"sentinel-core/internal/usecase"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// principalKey holds the authenticated entity.Principal in the request locals
+const principalKey = "sentinel.principal"
+
+// AdminAuth guards the admin route group with a static bearer token.
+// An empty token disables the admin API entirely rather than leaving it open.
+func AdminAuth(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "admin API is disabled"})
+		}
+
+		provided, isBearer := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !isBearer || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+		return c.Next()
+	}
+}
+
+// Authenticate requires an API key (X-API-Key, or a bearer token with the key prefix) or
+// an identity provider JWT as bearer token; either authenticator may be nil when that
+// method is off. The caller's identity is stored for the handlers, overriding whatever
+// the request body claims.
+func Authenticate(keys *usecase.APIKeyService, tokens repository.TokenVerifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey := c.Get("X-API-Key")
+		bearer, isBearer := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if apiKey == "" && isBearer && strings.HasPrefix(bearer, entity.APIKeyPrefix) {
+			apiKey, bearer = bearer, ""
+		}
+
+		var principal entity.Principal
+		var err error
+		switch {
+		case apiKey != "" && keys != nil:
+			principal, err = keys.Authenticate(c.Context(), apiKey)
+		case bearer != "" && tokens != nil:
+			principal, err = tokens.VerifyToken(c.Context(), bearer)
+		default:
+			err = entity.ErrUnauthorized
+		}
+
+		if errors.Is(err, entity.ErrUnauthorized) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": entity.ErrUnauthorized.Error()})
+		}
+		if err != nil {
+			log.Printf("[AUTH] Authentication failed: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal gateway error"})
+		}
+		c.Locals(principalKey, principal)
+		return c.Next()
+	}
+}
+
+// --- Private Helpers ---
+
+// principalFrom returns the authenticated caller, if the route is authenticated
+func principalFrom(c *fiber.Ctx) (entity.Principal, bool) {
+	p, ok := c.Locals(principalKey).(entity.Principal)
+	return p, ok
+}
@@ -1,13 +1,16 @@
 package api
 
 import (
+	"expvar"
 	"os"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
-func SetupRouter(app *fiber.App, handler *PromptHandler) {
+// SetupRouter registers the routes; auth guards the /v1 API and may be nil to leave it open.
+func SetupRouter(app *fiber.App, handler *PromptHandler, feedback *FeedbackHandler, admin *AdminHandler, rules *RulesHandler, keys *KeyHandler, tenants *TenantHandler, encryption *EncryptionHandler, auth fiber.Handler) {
 	// Middleware
 	app.Use(logger.New())
 
@@ -21,6 +24,36 @@ func SetupRouter(app *fiber.App, handler *PromptHandler) {
 
 	// API Versioning
 	v1 := app.Group("/v1")
+	if auth != nil {
+		v1.Use(auth)
+	}
 	// Endpoints
 	v1.Post("/chat", handler.HandlePrompt)
+	v1.Post("/feedback", feedback.HandleFeedback)
+
+	// Admin API (separately authenticated)
+	adm := app.Group("/admin", AdminAuth(os.Getenv("ADMIN_API_TOKEN")))
+	adm.Get("/metrics", adaptor.HTTPHandler(expvar.Handler()))
+	adm.Get("/cache", admin.ListCache)
+	adm.Post("/cache/delete", admin.DeleteCacheByFilter)
+	adm.Post("/cache/invalidate-templates", admin.InvalidateTemplates)
+	adm.Post("/cache/import", admin.ImportCache)
+	adm.Get("/cache/export", admin.ExportCache)
+	adm.Delete("/cache/scopes/:user_id", admin.PurgeScope)
+	adm.Get("/keys", keys.ListKeys)
+	adm.Post("/keys", keys.CreateKey)
+	adm.Get("/keys/:id", keys.GetKey)
+	adm.Post("/keys/:id/rotate", keys.RotateKey)
+	adm.Delete("/keys/:id", keys.RevokeKey)
+	adm.Get("/tenants", tenants.ListTenants)
+	adm.Get("/tenants/:id", tenants.GetTenant)
+	adm.Put("/tenants/:id", tenants.PutTenant)
+	adm.Delete("/tenants/:id", tenants.DeleteTenant)
+	adm.Post("/encryption/tenants/:id/rotate", encryption.RotateDataKey)
+	adm.Post("/encryption/reencrypt", encryption.Reencrypt)
+	adm.Get("/rules", rules.ListRules)
+	adm.Post("/rules/reload", rules.ReloadRules)
+	adm.Post("/rules/evaluate", rules.EvaluateRules)
+	adm.Get("/cache/:id", admin.GetCacheEntry)
+	adm.Delete("/cache/:id", admin.DeleteCacheEntry)
 }
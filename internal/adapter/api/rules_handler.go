@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RulesHandler exposes the request rules engine to admins; engine may be nil when no
+// rules file is configured.
+type RulesHandler struct {
+	engine *usecase.RuleEngine
+}
+
+func NewRulesHandler(engine *usecase.RuleEngine) *RulesHandler {
+	return &RulesHandler{engine: engine}
+}
+
+// dryRunRequest is the body of POST /admin/rules/evaluate. Rules, when present, are
+// evaluated instead of the loaded ones, so a rules change can be tried before deploying it.
+type dryRunRequest struct {
+	Input entity.RuleInput     `json:"input"`
+	Rules []entity.RequestRule `json:"rules"`
+}
+
+// ListRules handles GET /admin/rules
+func (h *RulesHandler) ListRules(c *fiber.Ctx) error {
+	if h.engine == nil {
+		return rulesDisabled(c)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"rules": h.engine.Rules()})
+}
+
+// ReloadRules handles POST /admin/rules/reload; an invalid file keeps the previous rules.
+func (h *RulesHandler) ReloadRules(c *fiber.Ctx) error {
+	if h.engine == nil {
+		return rulesDisabled(c)
+	}
+	n, err := h.engine.Reload()
+	if errors.Is(err, entity.ErrInvalidRequest) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		log.Printf("[RULES] Reload failed: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reload failed"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"loaded": n})
+}
+
+// EvaluateRules handles POST /admin/rules/evaluate with a dryRunRequest body
+func (h *RulesHandler) EvaluateRules(c *fiber.Ctx) error {
+	if h.engine == nil {
+		return rulesDisabled(c)
+	}
+	var req dryRunRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	var candidate *entity.RequestRuleSet
+	if req.Rules != nil {
+		candidate = &entity.RequestRuleSet{Rules: req.Rules}
+	}
+	decision, err := h.engine.DryRun(req.Input, candidate)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(decision)
+}
+
+// --- Private Helpers ---
+
+func rulesDisabled(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "rules engine is disabled (set RULES_FILE)"})
+}
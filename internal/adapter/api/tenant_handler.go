@@ -0,0 +1,75 @@
+package api
+
+import (
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantHandler manages tenant configurations; tenants may be nil when multi-tenancy is off.
+type TenantHandler struct {
+	tenants *usecase.TenantService
+}
+
+func NewTenantHandler(tenants *usecase.TenantService) *TenantHandler {
+	return &TenantHandler{tenants: tenants}
+}
+
+// ListTenants handles GET /admin/tenants
+func (h *TenantHandler) ListTenants(c *fiber.Ctx) error {
+	if h.tenants == nil {
+		return tenantsDisabled(c)
+	}
+	tenants, err := h.tenants.List(c.Context())
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"tenants": tenants})
+}
+
+func (h *TenantHandler) GetTenant(c *fiber.Ctx) error {
+	if h.tenants == nil {
+		return tenantsDisabled(c)
+	}
+	tenant, err := h.tenants.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(tenant)
+}
+
+// PutTenant handles PUT /admin/tenants/:id with an entity.Tenant body, replacing the
+// whole configuration.
+func (h *TenantHandler) PutTenant(c *fiber.Ctx) error {
+	if h.tenants == nil {
+		return tenantsDisabled(c)
+	}
+	var tenant entity.Tenant
+	if err := c.BodyParser(&tenant); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	tenant.ID = c.Params("id")
+	saved, err := h.tenants.Put(c.Context(), tenant)
+	if err != nil {
+		return adminError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(saved)
+}
+
+// DeleteTenant handles DELETE /admin/tenants/:id
+func (h *TenantHandler) DeleteTenant(c *fiber.Ctx) error {
+	if h.tenants == nil {
+		return tenantsDisabled(c)
+	}
+	if err := h.tenants.Delete(c.Context(), c.Params("id")); err != nil {
+		return adminError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// --- Private Helpers ---
+
+func tenantsDisabled(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "multi-tenancy is disabled (set TENANTS_ENABLED)"})
+}
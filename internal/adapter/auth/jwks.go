@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Unknown key IDs trigger a refetch at most this often, so forged kids cannot hammer the IdP
+const minRefetchInterval = time.Minute
+
+var errUnknownKey = errors.New("no JWKS key for token")
+
+// KeySet resolves the public key a token was signed with.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// jwk is the subset of RFC 7517 fields needed for RSA, EC and Ed25519 signature keys
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// StaticJWKS is a key set loaded once, for air-gapped deployments.
+type StaticJWKS struct {
+	keys map[string]crypto.PublicKey
+}
+
+// LoadJWKSFile reads a JWKS document ({"keys": [...]}) from disk.
+func LoadJWKSFile(path string) (*StaticJWKS, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticJWKS{keys: keys}, nil
+}
+
+func (s *StaticJWKS) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	return lookupKey(s.keys, kid)
+}
+
+// RemoteJWKS fetches the identity provider's JWKS endpoint, refreshing it periodically
+// and whenever a token names a key it has not seen (key rotation at the IdP).
+type RemoteJWKS struct {
+	url    string
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	attemptedAt time.Time // Last fetch, successful or not
+
+	refetch singleflight.Group // Concurrent unknown kids share one fetch
+}
+
+func NewRemoteJWKS(url string) *RemoteJWKS {
+	return &RemoteJWKS{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Refresh fetches the key set now, keeping the previous keys on failure.
+func (r *RemoteJWKS) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	r.attemptedAt = time.Now()
+	r.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+	return nil
+}
+
+// Start refreshes the key set every interval until ctx is cancelled.
+func (r *RemoteJWKS) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Refresh(ctx); err != nil {
+					log.Printf("[JWT] JWKS refresh failed, keeping previous keys: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (r *RemoteJWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, due, err := r.lookup(kid)
+	if err == nil || !due {
+		return key, err
+	}
+
+	// The fetch outlives whichever request started it, the others wait on it
+	r.refetch.Do("jwks", func() (any, error) {
+		if _, due, err := r.lookup(kid); err == nil || !due {
+			return nil, nil // Another flight just refetched
+		}
+		if err := r.Refresh(context.WithoutCancel(ctx)); err != nil {
+			log.Printf("[JWT] JWKS refetch for kid %q failed: %v", kid, err)
+		}
+		return nil, nil
+	})
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return lookupKey(r.keys, kid)
+}
+
+// --- Private Helpers ---
+
+// lookup finds a key in the current set and reports whether a refetch is allowed yet
+func (r *RemoteJWKS) lookup(kid string) (crypto.PublicKey, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, err := lookupKey(r.keys, kid)
+	return key, time.Since(r.attemptedAt) > minRefetchInterval, err
+}
+
+// lookupKey finds a key by ID; tokens without a kid are accepted when the set holds one key
+func lookupKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, error) {
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w (kid %q)", errUnknownKey, kid)
+}
+
+// parseJWKS decodes the signature keys of a JWKS document, skipping encryption and
+// unsupported keys rather than failing the whole set
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("[JWT] Skipping JWKS key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("invalid JWKS: no usable signature keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() < 3 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, errors.New("invalid EC coordinates")
+		}
+		// SEC 1 uncompressed point: 0x04 || X || Y, each left-padded to the field size
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	case "OKP":
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("unsupported OKP key %q", k.Crv)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url key parameter")
+	}
+	return b, nil
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Asymmetric algorithms only: a JWKS never holds the shared secret HS* would need
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// JWTConfig says which tokens are accepted and how their claims map to a caller.
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration // Clock skew tolerated on exp/nbf/iat
+
+	// Claim names; dotted paths reach into nested objects ("realm_access.roles")
+	UserClaim   string
+	TenantClaim string
+	RolesClaim  string
+
+	// A role naming a rate tier puts the caller in it; the largest budget wins
+	RateTiers map[string]int
+}
+
+// JWTVerifier authenticates bearer tokens from an OIDC identity provider.
+type JWTVerifier struct {
+	keys KeySet
+	cfg  JWTConfig
+}
+
+func NewJWTVerifier(keys KeySet, cfg JWTConfig) (*JWTVerifier, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("JWT issuer and audience are required")
+	}
+	if cfg.UserClaim == "" {
+		cfg.UserClaim = "sub"
+	}
+	return &JWTVerifier{keys: keys, cfg: cfg}, nil
+}
+
+// VerifyToken checks the signature, issuer, audience and expiry of a token and maps
+// its claims to the caller. Every rejection is an entity.ErrUnauthorized.
+func (v *JWTVerifier) VerifyToken(ctx context.Context, raw string) (entity.Principal, error) {
+	token, err := jwt.Parse(raw,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return v.keys.Key(ctx, kid)
+		},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(v.cfg.Issuer),
+		jwt.WithAudience(v.cfg.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.cfg.Leeway),
+	)
+	if err != nil {
+		log.Printf("[JWT] Rejected token: %v", err)
+		return entity.Principal{}, entity.ErrUnauthorized
+	}
+	claims, _ := token.Claims.(jwt.MapClaims)
+
+	user := claimString(claims, v.cfg.UserClaim)
+	if user == "" {
+		return entity.Principal{}, fmt.Errorf("%w: token has no %s claim", entity.ErrUnauthorized, v.cfg.UserClaim)
+	}
+	roles := claimStrings(claims, v.cfg.RolesClaim)
+	return entity.Principal{
+		UserID:   user,
+		TenantID: claimString(claims, v.cfg.TenantClaim),
+		Roles:    roles,
+		RateTier: v.rateTier(roles),
+	}, nil
+}
+
+// --- Private Helpers ---
+
+func (v *JWTVerifier) rateTier(roles []string) string {
+	tier, budget := "", -1
+	for _, role := range roles {
+		if n, ok := v.cfg.RateTiers[role]; ok && n > budget {
+			tier, budget = role, n
+		}
+	}
+	return tier
+}
+
+// claimValue follows a dotted path through nested claim objects
+func claimValue(claims jwt.MapClaims, path string) any {
+	if path == "" {
+		return nil
+	}
+	var cur any = map[string]any(claims)
+	for part := range strings.SplitSeq(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = obj[part]
+	}
+	return cur
+}
+
+func claimString(claims jwt.MapClaims, path string) string {
+	s, _ := claimValue(claims, path).(string)
+	return s
+}
+
+// claimStrings reads a list claim, also accepting space or comma separated strings ("scope" style)
+func claimStrings(claims jwt.MapClaims, path string) []string {
+	switch v := claimValue(claims, path).(type) {
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"sentinel-core/internal/domain/entity"
 
 	"google.golang.org/genai"
 )
@@ -34,18 +35,31 @@ func NewEmbedderFromClient(c *genai.Client, model string) *Embedder {
 	}
 }
 
+// Dimension probes the model once to learn the size of the vectors it produces.
+func (e *Embedder) Dimension(ctx context.Context) (uint64, error) {
+	vec, err := e.CreateEmbedding(ctx, "dimension probe")
+	if err != nil {
+		return 0, err
+	}
+	return uint64(len(vec)), nil
+}
+
 func (e *Embedder) CreateEmbedding(ctx context.Context, text string) ([]float32, error) {
-    res, err := e.client.Models.EmbedContent(ctx, e.model, genai.Text(text), &genai.EmbedContentConfig{
-        TaskType: "RETRIEVAL_QUERY", 
-    })
-    
-    if err != nil {
-        return nil, err
-    }
-    
-    if len(res.Embeddings) == 0 || len(res.Embeddings[0].Values) == 0 {
-        return nil, fmt.Errorf("no embedding values returned from model")
-    }
-    
-    return res.Embeddings[0].Values, nil
-}
\ No newline at end of file
+	return e.CreateEmbeddingFor(ctx, text, entity.TaskRetrievalQuery)
+}
+
+func (e *Embedder) CreateEmbeddingFor(ctx context.Context, text string, task entity.EmbeddingTask) ([]float32, error) {
+	res, err := e.client.Models.EmbedContent(ctx, e.model, genai.Text(text), &genai.EmbedContentConfig{
+		TaskType: string(task),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Embeddings) == 0 || len(res.Embeddings[0].Values) == 0 {
+		return nil, fmt.Errorf("no embedding values returned from model")
+	}
+
+	return res.Embeddings[0].Values, nil
+}
@@ -41,6 +41,8 @@ func (g *GeminiClient) Generate(ctx context.Context, prompt string) (*entity.AIR
 	return &entity.AIResponse{
 		Content:    result.Candidates[0].Content.Parts[0].Text, // simplified
 		TokenCount: int(result.UsageMetadata.TotalTokenCount),
+		Model:      g.model,
+		Provider:   "gemini",
 		Cached:     false,
 	}, nil
 }
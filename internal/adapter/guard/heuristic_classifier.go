@@ -0,0 +1,55 @@
+package guard
+
+import (
+	"context"
+	"regexp"
+	"sentinel-core/internal/domain/entity"
+	"strings"
+)
+
+// heuristicRule is one known injection pattern and how strongly it indicates an attack
+type heuristicRule struct {
+	signal  string
+	pattern *regexp.Regexp
+	weight  float64
+}
+
+// No single rule reaches the default block threshold (0.85): every pattern also matches
+// benign questions ("make eslint ignore all rules"), so one hit only flags and blocking
+// takes at least two independent signals.
+var defaultRules = []heuristicRule{
+	{"instruction_override", regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b.{0,30}\b(previous|prior|above|earlier|all|your|system)\b.{0,20}\b(instructions?|prompts?|rules|directives|guidelines)\b`), 0.7},
+	{"jailbreak_persona", regexp.MustCompile(`(?i:\b(do anything now|developer mode|jailbreak|jailbroken|unfiltered mode)\b)|\bDAN\b`), 0.8}, // DAN stays case-sensitive ("dan" is a common word)
+	{"role_play_override", regexp.MustCompile(`(?i)\b(you are now|from now on,? you|act as|pretend (to be|you are)|roleplay as)\b.{0,40}\b(unrestricted|uncensored|evil|without (any )?(rules|limits|restrictions|filters))\b`), 0.8},
+	{"prompt_exfiltration", regexp.MustCompile(`(?i)\b(reveal|print|show|repeat|output|leak)\b.{0,30}\b(system prompt|your (instructions|prompt|rules)|hidden (instructions|prompt))\b`), 0.7},
+	{"verdict_forcing", regexp.MustCompile(`(?i:respond|reply|answer|output)\s+(?i:only\s+|just\s+)?(?i:with\s+)?["'\x{201C}]?(YES|MATCH|TRUE)\b`), 0.6},
+	{"role_markers", regexp.MustCompile(`(?im)(^\s*(system|assistant)\s*:|<\|im_start\|>|<\|system\|>|\[/?INST\]|<</?SYS>>)`), 0.6},
+	{"delimiter_escape", regexp.MustCompile(`(?i)(end of (user )?(input|prompt|query)|-{3,}\s*new instructions|#{2,}\s*instructions)`), 0.5},
+	{"encoded_payload", regexp.MustCompile(`[A-Za-z0-9+/]{120,}={0,2}`), 0.3},
+}
+
+// HeuristicClassifier scores prompts against known injection and jailbreak phrasings.
+// Matched rules combine as a noisy-OR, so several weak signals add up to a strong one.
+type HeuristicClassifier struct {
+	rules []heuristicRule
+}
+
+func NewHeuristicClassifier() *HeuristicClassifier {
+	return &HeuristicClassifier{rules: defaultRules}
+}
+
+func (h *HeuristicClassifier) Classify(ctx context.Context, prompt string) (entity.InjectionScore, error) {
+	// Zero-width characters are a common way to split trigger words
+	normalized := strings.NewReplacer("\u200b", "", "\u200c", "", "\u200d", "", "\ufeff", "").Replace(prompt)
+
+	score := entity.InjectionScore{}
+	benign := 1.0
+	for _, rule := range h.rules {
+		if rule.pattern.MatchString(normalized) {
+			benign *= 1 - rule.weight
+			score.Signals = append(score.Signals, rule.signal)
+		}
+	}
+	score.Risk = 1 - benign
+	return score, nil
+}
@@ -0,0 +1,85 @@
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// keyFile is the on-disk layout: every master key ever used, and the one wrapping new data keys.
+//
+//	{"current": "2026-10", "keys": {"2026-04": "<base64 32 bytes>", "2026-10": "<base64 32 bytes>"}}
+type keyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LocalKeyring wraps data keys with AES-256-GCM master keys read from a file, for
+// deployments without a KMS. Rotating the master key means adding a key and making it
+// current; retired keys must stay in the file until every data key was re-wrapped.
+type LocalKeyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+func LoadKeyFile(path string) (*LocalKeyring, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	var file keyFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("invalid key file: %w", err)
+	}
+
+	ring := &LocalKeyring{current: file.Current, keys: make(map[string]cipher.AEAD, len(file.Keys))}
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("invalid key file: master key %q must be 32 bytes of base64", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		ring.keys[id] = aead
+	}
+	if _, ok := ring.keys[file.Current]; !ok {
+		return nil, fmt.Errorf("invalid key file: current master key %q is not in keys", file.Current)
+	}
+	return ring, nil
+}
+
+func (k *LocalKeyring) WrapKey(_ context.Context, dataKey []byte) ([]byte, string, error) {
+	aead := k.keys[k.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte(k.current)), k.current, nil
+}
+
+func (k *LocalKeyring) UnwrapKey(_ context.Context, masterKeyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[masterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q is not in the key file", masterKeyID)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("malformed wrapped key")
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(masterKeyID))
+}
+
+func (k *LocalKeyring) CurrentKeyID() string {
+	return k.current
+}
@@ -0,0 +1,66 @@
+package pii
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sentinel-core/internal/domain/entity"
+	"slices"
+	"strings"
+)
+
+// DictionaryDetector matches a custom list of sensitive terms (client names, project
+// code names, internal hostnames) as whole words, ignoring case.
+type DictionaryDetector struct {
+	pattern *regexp.Regexp // nil when the dictionary is empty
+}
+
+func NewDictionaryDetector(terms []string) *DictionaryDetector {
+	quoted := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t = strings.TrimSpace(t); t != "" {
+			quoted = append(quoted, regexp.QuoteMeta(t))
+		}
+	}
+	if len(quoted) == 0 {
+		return &DictionaryDetector{}
+	}
+
+	// Longest first, so "Acme Bank Berhad" wins over "Acme Bank"
+	slices.SortFunc(quoted, func(a, b string) int { return len(b) - len(a) })
+	return &DictionaryDetector{pattern: regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)}
+}
+
+// LoadDictionary reads one term per line; blank lines and #comments are skipped.
+func LoadDictionary(path string) (*DictionaryDetector, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PII dictionary: %w", err)
+	}
+	defer f.Close()
+
+	var terms []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			terms = append(terms, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read PII dictionary: %w", err)
+	}
+	return NewDictionaryDetector(terms), nil
+}
+
+func (d *DictionaryDetector) Detect(text string) []entity.PIIMatch {
+	if d.pattern == nil {
+		return nil
+	}
+	var matches []entity.PIIMatch
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		matches = append(matches, entity.PIIMatch{Type: entity.PIICustom, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
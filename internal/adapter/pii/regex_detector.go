@@ -0,0 +1,164 @@
+package pii
+
+import (
+	"fmt"
+	"regexp"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"strings"
+)
+
+// RegexDetector reports pattern matches of one PII type, optionally confirmed by a
+// validator (checksums, date ranges) to keep false positives out of prompts.
+type RegexDetector struct {
+	piiType entity.PIIType
+	pattern *regexp.Regexp
+	valid   func(match string) bool // nil accepts every match
+}
+
+func NewRegexDetector(piiType entity.PIIType, pattern string, valid func(string) bool) (*RegexDetector, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern: %w", piiType, err)
+	}
+	return &RegexDetector{piiType: piiType, pattern: re, valid: valid}, nil
+}
+
+func (d *RegexDetector) Detect(text string) []entity.PIIMatch {
+	var matches []entity.PIIMatch
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		if d.valid != nil && !d.valid(text[loc[0]:loc[1]]) {
+			continue
+		}
+		matches = append(matches, entity.PIIMatch{Type: d.piiType, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+func NewEmailDetector() *RegexDetector {
+	return mustDetector(entity.PIIEmail, `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`, nil)
+}
+
+// NewPhoneDetector matches international (+60 12-345 6789) and local (012-3456789, (03) 1234 5678) numbers.
+func NewPhoneDetector() *RegexDetector {
+	return mustDetector(entity.PIIPhone, `(?:\+\d{1,3}[ .\-]?)?(?:\(\d{1,4}\)[ .\-]?|\d{2,4}[ .\-]?)\d{3,4}[ .\-]?\d{3,4}\b`, func(m string) bool {
+		// Leading + or 0 (or an area code in brackets) tells a phone number from an amount
+		if !strings.ContainsAny(m[:1], "+0(") || strings.HasPrefix(m, "00") {
+			return false
+		}
+		n := len(digitsOf(m))
+		return n >= 9 && n <= 15
+	})
+}
+
+// NewCardDetector matches 13-19 digit card numbers that pass the Luhn check.
+func NewCardDetector() *RegexDetector {
+	return mustDetector(entity.PIICard, `\b\d(?:[ \-]?\d){12,18}\b`, func(m string) bool {
+		return luhnValid(digitsOf(m))
+	})
+}
+
+// NewIBANDetector matches IBANs (compact or grouped by four) that pass the mod-97 check.
+func NewIBANDetector() *RegexDetector {
+	return mustDetector(entity.PIIIBAN, `\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`, func(m string) bool {
+		return ibanValid(strings.ReplaceAll(m, " ", ""))
+	})
+}
+
+// NewNationalIDDetector matches Malaysian MyKad numbers (YYMMDD-PB-NNNN) and US SSNs (AAA-GG-SSSS).
+func NewNationalIDDetector() *RegexDetector {
+	return mustDetector(entity.PIINationalID, `\b(?:\d{6}-\d{2}-\d{4}|\d{3}-\d{2}-\d{4})\b`, func(m string) bool {
+		if len(m) == len("YYMMDD-PB-NNNN") {
+			month, day := atoi2(m[2:4]), atoi2(m[4:6])
+			return month >= 1 && month <= 12 && day >= 1 && day <= 31
+		}
+		area, group, serial := m[0:3], m[4:6], m[7:11]
+		return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+	})
+}
+
+// Detectors builds the named built-in detectors (email, phone, card, iban, national_id);
+// no names means all of them.
+func Detectors(names ...string) ([]repository.PIIDetector, error) {
+	builtins := map[string]func() *RegexDetector{
+		"email":       NewEmailDetector,
+		"phone":       NewPhoneDetector,
+		"card":        NewCardDetector,
+		"iban":        NewIBANDetector,
+		"national_id": NewNationalIDDetector,
+	}
+	if len(names) == 0 {
+		// Checksummed and fixed-format detectors first: they win overlaps with phone numbers
+		names = []string{"card", "iban", "national_id", "email", "phone"}
+	}
+
+	detectors := make([]repository.PIIDetector, 0, len(names))
+	for _, name := range names {
+		build, ok := builtins[strings.TrimSpace(strings.ToLower(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown PII detector %q", name)
+		}
+		detectors = append(detectors, build())
+	}
+	return detectors, nil
+}
+
+// --- Private Helpers ---
+
+func mustDetector(piiType entity.PIIType, pattern string, valid func(string) bool) *RegexDetector {
+	d, err := NewRegexDetector(piiType, pattern, valid)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func digitsOf(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func luhnValid(digits string) bool {
+	sum, double := 0, false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return len(digits) > 0 && sum%10 == 0
+}
+
+// ibanValid applies ISO 13616: move the first four characters to the end,
+// map letters to 10..35 and check the number mod 97 equals 1.
+func ibanValid(iban string) bool {
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	rem := 0
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+func atoi2(s string) int {
+	return int(s[0]-'0')*10 + int(s[1]-'0')
+}
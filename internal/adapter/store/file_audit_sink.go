@@ -0,0 +1,136 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sentinel-core/internal/domain/entity"
+	"sync"
+)
+
+// Audit events carry raw text when enabled, so lines may be long
+const maxAuditLine = 16 << 20
+
+// FileAuditSink appends audit events to a JSONL file, one event per line.
+type FileAuditSink struct {
+	path string
+	sync bool // fsync after every event
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink opens (or creates) the log for appending. With syncWrites every event
+// is flushed to disk before the next one, trading throughput for durability. A partial
+// last line left by a crash mid-write is cut off, so the chain continues after the last
+// complete event.
+func NewFileAuditSink(path string, syncWrites bool) (*FileAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if err := truncateTornLine(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to repair audit log: %w", err)
+	}
+	return &FileAuditSink{path: path, sync: syncWrites, file: f}, nil
+}
+
+// OpenFileAuditLog opens an existing log read-only, for verification.
+func OpenFileAuditLog(path string) (*FileAuditSink, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &FileAuditSink{path: path}, nil
+}
+
+func (s *FileAuditSink) Append(_ context.Context, event entity.AuditEvent) error {
+	if s.file == nil {
+		return errors.New("audit log is opened read-only")
+	}
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	raw = append(raw, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(raw); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	if s.sync {
+		return s.file.Sync()
+	}
+	return nil
+}
+
+// Last reads the whole log once; it is only needed at startup.
+func (s *FileAuditSink) Last(ctx context.Context) (*entity.AuditEvent, error) {
+	var last *entity.AuditEvent
+	err := s.Scan(ctx, func(e entity.AuditEvent) error {
+		last = &e
+		return nil
+	})
+	return last, err
+}
+
+func (s *FileAuditSink) Scan(ctx context.Context, fn func(entity.AuditEvent) error) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxAuditLine)
+	for line := 1; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var event entity.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("corrupt audit log line %d: %w", line, err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// --- Private Helpers ---
+
+// truncateTornLine drops whatever follows the last newline: every complete event ends with one
+func truncateTornLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	end := info.Size()
+	buf := make([]byte, 64*1024)
+	for pos := end; pos > 0; {
+		n := min(int64(len(buf)), pos)
+		pos -= n
+		if _, err := f.ReadAt(buf[:n], pos); err != nil {
+			return err
+		}
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			return cutAt(f, pos+int64(i)+1, end)
+		}
+	}
+	return cutAt(f, 0, end)
+}
+
+func cutAt(f *os.File, size, end int64) error {
+	if size == end {
+		return nil
+	}
+	log.Printf("[AUDIT] Dropping a torn last line (%d bytes) left by an interrupted write", end-size)
+	return f.Truncate(size)
+}
@@ -0,0 +1,464 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sentinel-core/internal/domain/entity"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryEntry is one cached answer; exported fields make up the snapshot format.
+type memoryEntry struct {
+	ID             string         `json:"id"`
+	Vector         []float32      `json:"vector"`
+	DocumentVector []float32      `json:"document_vector,omitempty"`
+	Payload        map[string]any `json:"payload"` // prompt, content, created_at and caller metadata
+}
+
+// MemoryStore is an in-process repository.VectorStore using brute-force cosine search.
+// It mirrors QdrantStore semantics (exact-match payload filters, created_at freshness,
+// score threshold, feedback demotion) so the cache pipeline can run without Qdrant,
+// for tests and single-binary deployments. Entries can be snapshotted to a JSON file.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	entries      map[string]*memoryEntry
+	snapshotPath string // Empty disables snapshots
+	searchVector string // Named vector searched by default when document vectors are stored
+}
+
+// NewMemoryStore creates an empty store, restoring the snapshot at snapshotPath if one exists.
+func NewMemoryStore(snapshotPath string) (*MemoryStore, error) {
+	m := &MemoryStore{
+		entries:      make(map[string]*memoryEntry),
+		snapshotPath: snapshotPath,
+		searchVector: entity.QueryVectorName,
+	}
+	if snapshotPath == "" {
+		return m, nil
+	}
+
+	data, err := os.ReadFile(snapshotPath)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var entries []*memoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	for _, e := range entries {
+		m.entries[e.ID] = e
+	}
+	log.Printf("[MEMORY-STORE] Restored %d entries from %s", len(entries), snapshotPath)
+	return m, nil
+}
+
+// SearchDocumentVectors makes lookups compare against stored document vectors by default
+// (the dual embedding layout with EMBEDDING_SEARCH_VECTOR=document).
+func (m *MemoryStore) SearchDocumentVectors() {
+	m.searchVector = entity.DocumentVectorName
+}
+
+func (m *MemoryStore) Search(ctx context.Context, query entity.SearchQuery) ([]entity.CacheHit, error) {
+	var oldest int64
+	if query.MaxAge > 0 {
+		oldest = time.Now().Add(-query.MaxAge).Unix()
+	}
+	using := query.Using
+	if using == "" {
+		using = m.searchVector
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var hits []entity.CacheHit
+	for _, e := range m.entries {
+		// 1. Filters: scope, freshness and feedback demotion
+		if !matchesAll(e.Payload, query.Filters) {
+			continue
+		}
+		if oldest > 0 && payloadInt(e.Payload, "created_at") < oldest {
+			continue
+		}
+		if payloadInt(e.Payload, "feedback_score") < 0 {
+			continue
+		}
+
+		// 2. Similarity
+		vec := e.Vector
+		if using == entity.DocumentVectorName && e.DocumentVector != nil {
+			vec = e.DocumentVector
+		}
+		score := cosine(query.Vector, vec)
+		if score < query.Threshold {
+			continue
+		}
+
+		entry := e.toCacheEntry()
+		hits = append(hits, entity.CacheHit{
+			Response: &entity.AIResponse{
+				ID:      entry.ID,
+				Content: entry.Content,
+				Cached:  true,
+			},
+			Score:     score,
+			Prompt:    entry.Prompt,
+			CreatedAt: entry.CreatedAt,
+			Payload:   entry.Metadata,
+		})
+	}
+
+	slices.SortFunc(hits, func(a, b entity.CacheHit) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return strings.Compare(a.Response.ID, b.Response.ID)
+		}
+	})
+	if limit := max(query.Limit, 1); len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func (m *MemoryStore) Save(ctx context.Context, record entity.CacheRecord) error {
+	return m.SaveBatch(ctx, []entity.CacheRecord{record})
+}
+
+func (m *MemoryStore) SaveBatch(ctx context.Context, records []entity.CacheRecord) error {
+	entries := make([]*memoryEntry, len(records))
+	for i, record := range records {
+		entries[i] = newMemoryEntry(record)
+	}
+
+	m.mu.Lock()
+	for _, e := range entries {
+		m.entries[e.ID] = e
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+func newMemoryEntry(record entity.CacheRecord) *memoryEntry {
+	payload := map[string]any{
+		"prompt":     record.Prompt,
+		"content":    record.Response.Content,
+		"created_at": time.Now().Unix(),
+	}
+	for k, v := range record.Metadata {
+		payload[k] = v
+	}
+
+	id := record.Response.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	return &memoryEntry{
+		ID:             id,
+		Vector:         slices.Clone(record.Vector),
+		DocumentVector: slices.Clone(record.DocumentVector),
+		Payload:        normalizePayload(payload),
+	}
+}
+
+func (m *MemoryStore) Get(ctx context.Context, id string) (*entity.CacheEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	e, ok := m.entries[id]
+	if !ok {
+		return nil, entity.ErrResourceNotFound
+	}
+	entry := e.toCacheEntry()
+	return &entry, nil
+}
+
+// List pages through entries in ID order; the cursor is the first ID of the next page.
+func (m *MemoryStore) List(ctx context.Context, filter entity.CacheFilter, limit int, cursor string) (*entity.CachePage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := m.matchingIDs(filter)
+	start, _ := slices.BinarySearch(ids, cursor)
+
+	page := &entity.CachePage{Entries: []entity.CacheEntry{}}
+	for i := start; i < len(ids); i++ {
+		if len(page.Entries) == limit {
+			page.NextCursor = ids[i]
+			break
+		}
+		page.Entries = append(page.Entries, m.entries[ids[i]].toCacheEntry())
+	}
+	return page, nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.entries, id)
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteByFilter(ctx context.Context, filter entity.CacheFilter) error {
+	if filter.IsEmpty() {
+		return fmt.Errorf("%w: refusing to delete with an empty filter", entity.ErrInvalidRequest)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.matchingIDs(filter) {
+		delete(m.entries, id)
+	}
+	return nil
+}
+
+func (m *MemoryStore) RecordHit(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[id]
+	if !ok {
+		return entity.ErrResourceNotFound
+	}
+	// Numbers are stored as float64, the way a snapshot restores them
+	e.Payload[entity.HitCountKey] = float64(payloadInt(e.Payload, entity.HitCountKey) + 1)
+	e.Payload[entity.LastHitAtKey] = float64(time.Now().Unix())
+	return nil
+}
+
+func (m *MemoryStore) ApplyFeedback(ctx context.Context, id string, vote entity.Vote) (entity.FeedbackTally, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[id]
+	if !ok {
+		return entity.FeedbackTally{}, entity.ErrResourceNotFound
+	}
+
+	tally := entity.FeedbackTally{
+		Up:   payloadInt(e.Payload, "feedback_up"),
+		Down: payloadInt(e.Payload, "feedback_down"),
+	}
+	up, down := vote.Delta()
+	tally.Up += up
+	tally.Down += down
+	e.Payload["feedback_up"] = float64(tally.Up)
+	e.Payload["feedback_down"] = float64(tally.Down)
+	e.Payload["feedback_score"] = float64(tally.Score())
+	return tally, nil
+}
+
+// Snapshot writes every entry to the snapshot file, atomically replacing the previous one.
+func (m *MemoryStore) Snapshot() error {
+	if m.snapshotPath == "" {
+		return nil
+	}
+
+	m.mu.RLock()
+	entries := make([]*memoryEntry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	data, err := json.Marshal(entries)
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.snapshotPath), ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.snapshotPath)
+}
+
+// StartSnapshots snapshots the store every interval until ctx is done, then once more.
+func (m *MemoryStore) StartSnapshots(ctx context.Context, interval time.Duration) {
+	if m.snapshotPath == "" || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				if err := m.Snapshot(); err != nil {
+					log.Printf("[MEMORY-STORE] Final snapshot failed: %v", err)
+				}
+				return
+			}
+			if err := m.Snapshot(); err != nil {
+				log.Printf("[MEMORY-STORE] Snapshot failed: %v", err)
+			}
+		}
+	}()
+}
+
+// --- Private Helpers ---
+
+// matchingIDs returns the sorted IDs of entries matching an admin filter; callers hold the lock.
+func (m *MemoryStore) matchingIDs(filter entity.CacheFilter) []string {
+	var ids []string
+	for id, e := range m.entries {
+		if filter.UserID != "" && !payloadMatches(e.Payload["user_id"], filter.UserID) {
+			continue
+		}
+		if !matchesAll(e.Payload, filter.Metadata) {
+			continue
+		}
+		if excludedBy(e.Payload, filter.Exclude) {
+			continue
+		}
+		if filter.Text != "" && !containsAllWords(payloadString(e.Payload, "prompt"), filter.Text) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (e *memoryEntry) toCacheEntry() entity.CacheEntry {
+	entry := entity.CacheEntry{
+		ID:        e.ID,
+		Prompt:    payloadString(e.Payload, "prompt"),
+		Content:   payloadString(e.Payload, "content"),
+		CreatedAt: time.Unix(payloadInt(e.Payload, "created_at"), 0),
+		Metadata:  make(map[string]any),
+	}
+	for k, v := range e.Payload {
+		if !reservedPayloadKeys[k] {
+			entry.Metadata[k] = v
+		}
+	}
+	return entry
+}
+
+func matchesAll(payload map[string]any, filters map[string]string) bool {
+	for k, v := range filters {
+		if _, ok := payload[k]; !ok && entity.MatchesUnrecorded(k, v) {
+			continue
+		}
+		if !payloadMatches(payload[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// excludedBy mirrors Qdrant must_not: entries lacking the field are kept
+func excludedBy(payload map[string]any, exclude map[string]string) bool {
+	for k, v := range exclude {
+		if payloadMatches(payload[k], v) {
+			return true
+		}
+	}
+	return false
+}
+
+// payloadMatches mirrors Qdrant keyword matching, where an array matches if any element does
+func payloadMatches(value any, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == want
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsAllWords approximates Qdrant's full-text match: every query word must appear
+func containsAllWords(text, query string) bool {
+	words := strings.Fields(strings.ToLower(text))
+	for _, q := range strings.Fields(strings.ToLower(query)) {
+		if !slices.Contains(words, q) {
+			return false
+		}
+	}
+	return true
+}
+
+func payloadString(payload map[string]any, key string) string {
+	s, _ := payload[key].(string)
+	return s
+}
+
+func payloadInt(payload map[string]any, key string) int64 {
+	switch v := payload[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// normalizePayload round-trips through JSON so live entries look exactly like
+// restored ones ([]any lists, float64 numbers) and callers can't mutate them.
+func normalizePayload(payload map[string]any) map[string]any {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return payload
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return payload
+	}
+	return out
+}
+
+func cosine(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
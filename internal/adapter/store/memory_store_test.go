@@ -0,0 +1,239 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sentinel-core/internal/domain/entity"
+	"slices"
+	"testing"
+)
+
+// testRecord caches an answer under a fixed ID, vector and metadata
+func testRecord(id, prompt string, vector []float32, meta map[string]any) entity.CacheRecord {
+	return entity.CacheRecord{
+		Prompt:   prompt,
+		Response: &entity.AIResponse{ID: id, Content: "answer to " + prompt},
+		Vector:   vector,
+		Metadata: meta,
+	}
+}
+
+func newTestMemoryStore(t *testing.T, records ...entity.CacheRecord) *MemoryStore {
+	t.Helper()
+	m, err := NewMemoryStore("")
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	if err := m.SaveBatch(context.Background(), records); err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+	return m
+}
+
+func hitIDs(hits []entity.CacheHit) []string {
+	ids := make([]string, len(hits))
+	for i, h := range hits {
+		ids[i] = h.Response.ID
+	}
+	return ids
+}
+
+func entryIDs(entries []entity.CacheEntry) []string {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestMemoryStoreSearch(t *testing.T) {
+	m := newTestMemoryStore(t,
+		testRecord("a", "exact", []float32{1, 0}, map[string]any{"user_id": "alice", entity.TenantIDKey: "acme"}),
+		testRecord("b", "close", []float32{0.9, 0.1}, map[string]any{"user_id": "alice", entity.TenantIDKey: "acme"}),
+		testRecord("c", "far", []float32{0, 1}, map[string]any{"user_id": "alice", entity.TenantIDKey: "acme"}),
+		testRecord("d", "other user", []float32{1, 0}, map[string]any{"user_id": "bob", entity.TenantIDKey: "acme"}),
+		testRecord("e", "legacy", []float32{1, 0}, map[string]any{"user_id": "carol"}),
+	)
+	ctx := context.Background()
+	if _, err := m.ApplyFeedback(ctx, "b", entity.Vote{Rating: entity.RatingDown}); err != nil {
+		t.Fatalf("ApplyFeedback: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query entity.SearchQuery
+		want  []string
+	}{
+		{
+			name:  "best score first, below threshold dropped",
+			query: entity.SearchQuery{Vector: []float32{1, 0}, Threshold: 0.5, Limit: 10, Filters: map[string]string{"user_id": "alice"}},
+			want:  []string{"a"},
+		},
+		{
+			name:  "ties ordered by id",
+			query: entity.SearchQuery{Vector: []float32{1, 0}, Threshold: 0.99, Limit: 10},
+			want:  []string{"a", "d", "e"},
+		},
+		{
+			name:  "limit",
+			query: entity.SearchQuery{Vector: []float32{1, 0}, Threshold: 0.99, Limit: 1},
+			want:  []string{"a"},
+		},
+		{
+			name:  "scope filter",
+			query: entity.SearchQuery{Vector: []float32{1, 0}, Limit: 10, Filters: map[string]string{"user_id": "bob", entity.TenantIDKey: "acme"}},
+			want:  []string{"d"},
+		},
+		{
+			name:  "untenanted entries belong to the default tenant",
+			query: entity.SearchQuery{Vector: []float32{1, 0}, Limit: 10, Filters: map[string]string{entity.TenantIDKey: entity.DefaultTenant}},
+			want:  []string{"e"},
+		},
+		{
+			name:  "no match",
+			query: entity.SearchQuery{Vector: []float32{1, 0}, Limit: 10, Filters: map[string]string{"user_id": "dave"}},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := m.Search(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if got := hitIDs(hits); !slices.Equal(got, tt.want) {
+				t.Errorf("hits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreListFilter(t *testing.T) {
+	m := newTestMemoryStore(t,
+		testRecord("1", "transfer to savings", []float32{1, 0}, map[string]any{"user_id": "alice", "action": "transfer"}),
+		testRecord("2", "check my balance", []float32{1, 0}, map[string]any{"user_id": "alice", "action": "balance"}),
+		testRecord("3", "transfer to checking", []float32{1, 0}, map[string]any{"user_id": "bob", "action": "transfer"}),
+		testRecord("4", "list entities", []float32{1, 0}, map[string]any{"user_id": "bob", entity.LexEntitiesKey: []any{"savings", "checking"}}),
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter entity.CacheFilter
+		want   []string
+	}{
+		{"everything", entity.CacheFilter{}, []string{"1", "2", "3", "4"}},
+		{"user", entity.CacheFilter{UserID: "alice"}, []string{"1", "2"}},
+		{"metadata", entity.CacheFilter{Metadata: map[string]string{"action": "transfer"}}, []string{"1", "3"}},
+		{"user and metadata", entity.CacheFilter{UserID: "bob", Metadata: map[string]string{"action": "transfer"}}, []string{"3"}},
+		{"array element", entity.CacheFilter{Metadata: map[string]string{entity.LexEntitiesKey: "savings"}}, []string{"4"}},
+		{"exclude keeps entries without the field", entity.CacheFilter{Exclude: map[string]string{"action": "transfer"}}, []string{"2", "4"}},
+		{"text needs every word", entity.CacheFilter{Text: "Transfer savings"}, []string{"1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := m.List(ctx, tt.filter, 10, "")
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if got := entryIDs(page.Entries); !slices.Equal(got, tt.want) {
+				t.Errorf("entries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		var got []string
+		cursor := ""
+		for {
+			page, err := m.List(ctx, entity.CacheFilter{}, 3, cursor)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			got = append(got, entryIDs(page.Entries)...)
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		if want := []string{"1", "2", "3", "4"}; !slices.Equal(got, want) {
+			t.Errorf("paged entries = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestMemoryStoreDeleteByFilter(t *testing.T) {
+	m := newTestMemoryStore(t,
+		testRecord("1", "a", []float32{1, 0}, map[string]any{"user_id": "alice", "action": "transfer"}),
+		testRecord("2", "b", []float32{1, 0}, map[string]any{"user_id": "alice", "action": "balance"}),
+		testRecord("3", "c", []float32{1, 0}, map[string]any{"user_id": "bob", "action": "transfer"}),
+	)
+	ctx := context.Background()
+
+	if err := m.DeleteByFilter(ctx, entity.CacheFilter{}); !errors.Is(err, entity.ErrInvalidRequest) {
+		t.Fatalf("empty filter: err = %v, want ErrInvalidRequest", err)
+	}
+	if err := m.DeleteByFilter(ctx, entity.CacheFilter{UserID: "alice", Metadata: map[string]string{"action": "transfer"}}); err != nil {
+		t.Fatalf("DeleteByFilter: %v", err)
+	}
+
+	page, err := m.List(ctx, entity.CacheFilter{}, 10, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got, want := entryIDs(page.Entries), []string{"2", "3"}; !slices.Equal(got, want) {
+		t.Errorf("remaining = %v, want %v", got, want)
+	}
+	if _, err := m.Get(ctx, "1"); !errors.Is(err, entity.ErrResourceNotFound) {
+		t.Errorf("Get deleted: err = %v, want ErrResourceNotFound", err)
+	}
+}
+
+func TestMemoryStoreSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	ctx := context.Background()
+
+	m, err := NewMemoryStore(path)
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	err = m.SaveBatch(ctx, []entity.CacheRecord{
+		testRecord("1", "transfer to savings", []float32{1, 0}, map[string]any{"user_id": "alice", entity.LexNumbersKey: []string{"500"}}),
+		testRecord("2", "check my balance", []float32{0, 1}, map[string]any{"user_id": "bob"}),
+	})
+	if err != nil {
+		t.Fatalf("SaveBatch: %v", err)
+	}
+	if err := m.RecordHit(ctx, "1"); err != nil {
+		t.Fatalf("RecordHit: %v", err)
+	}
+	if err := m.Snapshot(); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	restored, err := NewMemoryStore(path)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	for _, id := range []string{"1", "2"} {
+		want, _ := m.Get(ctx, id)
+		got, err := restored.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("Get %s after restore: %v", id, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("entry %s = %+v, want %+v", id, got, want)
+		}
+	}
+
+	// Restored vectors are searchable
+	hits, err := restored.Search(ctx, entity.SearchQuery{Vector: []float32{0, 1}, Threshold: 0.9, Limit: 5})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := hitIDs(hits); !slices.Equal(got, []string{"2"}) {
+		t.Errorf("hits after restore = %v, want [2]", got)
+	}
+}
@@ -0,0 +1,29 @@
+-- Semantic cache table mirroring the Qdrant collection layout:
+-- dense vectors + a JSONB payload holding scope, intent and bookkeeping fields.
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE IF NOT EXISTS semantic_cache (
+    id                 UUID PRIMARY KEY,
+    prompt             TEXT        NOT NULL,
+    content            TEXT        NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    payload            JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    embedding          vector({{.Dimension}}) NOT NULL,
+    document_embedding vector({{.Dimension}})
+);
+
+-- Nearest-neighbour search (cosine), matching the Qdrant collection distance
+CREATE INDEX IF NOT EXISTS semantic_cache_embedding_hnsw
+    ON semantic_cache USING hnsw (embedding vector_cosine_ops);
+CREATE INDEX IF NOT EXISTS semantic_cache_document_embedding_hnsw
+    ON semantic_cache USING hnsw (document_embedding vector_cosine_ops);
+
+-- Scope filters (payload @> '{"user_id": ...}') and the freshness window
+CREATE INDEX IF NOT EXISTS semantic_cache_payload_gin
+    ON semantic_cache USING gin (payload jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS semantic_cache_created_at
+    ON semantic_cache (created_at);
+
+-- Full-text search on prompts for the admin API
+CREATE INDEX IF NOT EXISTS semantic_cache_prompt_fts
+    ON semantic_cache USING gin (to_tsvector('simple', prompt));
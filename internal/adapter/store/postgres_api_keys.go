@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sentinel-core/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const apiKeyColumns = `id::text, hint, hash, owner, tenant_id, allowed_models, rate_tier,
+	created_at, expires_at, revoked_at, previous_hash, previous_valid_until`
+
+const upsertAPIKeySQL = `
+	INSERT INTO sentinel_api_keys (id, hint, hash, owner, tenant_id, allowed_models, rate_tier,
+		created_at, expires_at, revoked_at, previous_hash, previous_valid_until)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12)
+	ON CONFLICT (id) DO UPDATE SET
+		hint = EXCLUDED.hint, hash = EXCLUDED.hash, owner = EXCLUDED.owner,
+		tenant_id = EXCLUDED.tenant_id, allowed_models = EXCLUDED.allowed_models,
+		rate_tier = EXCLUDED.rate_tier, expires_at = EXCLUDED.expires_at,
+		revoked_at = EXCLUDED.revoked_at, previous_hash = EXCLUDED.previous_hash,
+		previous_valid_until = EXCLUDED.previous_valid_until`
+
+// PostgresAPIKeyStore keeps API keys in the sentinel_api_keys table, created by Migrate.
+type PostgresAPIKeyStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAPIKeyStore(pool *pgxpool.Pool) *PostgresAPIKeyStore {
+	return &PostgresAPIKeyStore{pool: pool}
+}
+
+// Migrate creates the key table and its hash indexes when missing.
+func (s *PostgresAPIKeyStore) Migrate(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS sentinel_api_keys (
+			id                   UUID PRIMARY KEY,
+			hint                 TEXT NOT NULL,
+			hash                 TEXT NOT NULL UNIQUE,
+			owner                TEXT NOT NULL,
+			tenant_id            TEXT NOT NULL DEFAULT '',
+			allowed_models       TEXT[] NOT NULL DEFAULT '{}',
+			rate_tier            TEXT NOT NULL DEFAULT '',
+			created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+			expires_at           TIMESTAMPTZ,
+			revoked_at           TIMESTAMPTZ,
+			previous_hash        TEXT,
+			previous_valid_until TIMESTAMPTZ
+		);
+		CREATE INDEX IF NOT EXISTS sentinel_api_keys_previous_hash_idx ON sentinel_api_keys (previous_hash) WHERE previous_hash IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS sentinel_api_keys_owner_idx ON sentinel_api_keys (owner)`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate API key table: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresAPIKeyStore) Save(ctx context.Context, key entity.APIKey) error {
+	if _, err := s.pool.Exec(ctx, upsertAPIKeySQL, apiKeyArgs(key)...); err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+	return nil
+}
+
+// Update locks the row from the read to the write, so concurrent updates queue up.
+func (s *PostgresAPIKeyStore) Update(ctx context.Context, id string, fn func(*entity.APIKey) error) (*entity.APIKey, error) {
+	if uuid.Validate(id) != nil {
+		return nil, entity.ErrResourceNotFound
+	}
+	var key entity.APIKey
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		var err error
+		key, err = scanAPIKey(tx.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM sentinel_api_keys WHERE id = $1 FOR UPDATE`, id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrResourceNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(&key); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, upsertAPIKeySQL, apiKeyArgs(key)...); err != nil {
+			return fmt.Errorf("failed to save API key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (s *PostgresAPIKeyStore) Get(ctx context.Context, id string) (*entity.APIKey, error) {
+	if uuid.Validate(id) != nil {
+		return nil, entity.ErrResourceNotFound
+	}
+	return s.findOne(ctx, `SELECT `+apiKeyColumns+` FROM sentinel_api_keys WHERE id = $1`, id)
+}
+
+// FindByHash also matches a rotated-out secret still inside its grace period.
+func (s *PostgresAPIKeyStore) FindByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	return s.findOne(ctx, `SELECT `+apiKeyColumns+` FROM sentinel_api_keys
+		WHERE hash = $1 OR (previous_hash = $1 AND previous_valid_until > now())
+		LIMIT 1`, hash)
+}
+
+func (s *PostgresAPIKeyStore) List(ctx context.Context, owner string) ([]entity.APIKey, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+apiKeyColumns+` FROM sentinel_api_keys
+		WHERE $1 = '' OR owner = $1 ORDER BY created_at`, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []entity.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// --- Private Helpers ---
+
+func (s *PostgresAPIKeyStore) findOne(ctx context.Context, sql string, arg string) (*entity.APIKey, error) {
+	key, err := scanAPIKey(s.pool.QueryRow(ctx, sql, arg))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, entity.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func scanAPIKey(row pgx.Row) (entity.APIKey, error) {
+	var key entity.APIKey
+	var previousHash *string
+	err := row.Scan(&key.ID, &key.Hint, &key.Hash, &key.Owner, &key.TenantID, &key.AllowedModels, &key.RateTier,
+		&key.CreatedAt, &key.ExpiresAt, &key.RevokedAt, &previousHash, &key.PreviousValidUntil)
+	if previousHash != nil {
+		key.PreviousHash = *previousHash
+	}
+	return key, err
+}
+
+func apiKeyArgs(key entity.APIKey) []any {
+	return []any{key.ID, key.Hint, key.Hash, key.Owner, key.TenantID, nonNil(key.AllowedModels), key.RateTier,
+		key.CreatedAt, key.ExpiresAt, key.RevokedAt, key.PreviousHash, key.PreviousValidUntil}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
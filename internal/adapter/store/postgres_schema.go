@@ -0,0 +1,135 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// Arbitrary key for the advisory lock that serialises migrations across gateway replicas
+const migrationLockKey = 7_310_442_019
+
+// Migrate applies pending schema migrations (pgvector extension, cache table, HNSW and
+// payload indexes) and validates that the stored vectors match the embedder's dimension.
+// Migrations are embedded SQL files applied in filename order, each in its own transaction.
+func (s *PostgresStore) Migrate(ctx context.Context, spec CollectionSpec) error {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// 1. One replica migrates at a time
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS sentinel_schema_migrations (
+			version    INT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	// 2. Apply whatever has not run yet
+	files, err := fs.Glob(postgresMigrations, "migrations/postgres/*.sql")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := path.Base(file)
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("migration %s: filename must start with a version number", name)
+		}
+
+		var applied bool
+		if err := conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM sentinel_schema_migrations WHERE version = $1)", version).Scan(&applied); err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		sql, err := renderMigration(file, spec)
+		if err != nil {
+			return err
+		}
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, sql); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO sentinel_schema_migrations (version, name) VALUES ($1, $2)", version, name)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", name, err)
+		}
+		log.Printf("[PGVECTOR] Applied migration %s", name)
+	}
+
+	// 3. The table must match whatever the configured embedder produces
+	var columnType string
+	err = conn.QueryRow(ctx, `SELECT format_type(atttypid, atttypmod) FROM pg_attribute
+		WHERE attrelid = 'semantic_cache'::regclass AND attname = 'embedding'`).Scan(&columnType)
+	if err != nil {
+		return fmt.Errorf("failed to inspect semantic_cache: %w", err)
+	}
+	if want := fmt.Sprintf("vector(%d)", spec.Dimension); columnType != want {
+		return fmt.Errorf("%w: semantic_cache.embedding is %s, embedder produces %s (recreate the table or re-import the cache)",
+			ErrCollectionMismatch, columnType, want)
+	}
+
+	// 4. Filtered lookups must not lose matches the HNSW scan filtered away
+	var extVersion string
+	if err := conn.QueryRow(ctx, "SELECT extversion FROM pg_extension WHERE extname = 'vector'").Scan(&extVersion); err != nil {
+		return fmt.Errorf("failed to detect pgvector version: %w", err)
+	}
+	s.scanSettings = hnswScanSettings(extVersion)
+	return nil
+}
+
+// hnswScanSettings keeps scanning the HNSW index until enough rows pass the payload
+// filters (pgvector 0.8+); older versions only get a wider candidate list.
+func hnswScanSettings(extVersion string) string {
+	major, rest, _ := strings.Cut(extVersion, ".")
+	minor, _, _ := strings.Cut(rest, ".")
+	maj, _ := strconv.Atoi(major)
+	mnr, _ := strconv.Atoi(minor)
+	if maj > 0 || mnr >= 8 {
+		return "SET LOCAL hnsw.iterative_scan = strict_order"
+	}
+	log.Printf("[PGVECTOR] pgvector %s has no iterative index scans; scoped lookups may miss entries (upgrade to 0.8+)", extVersion)
+	return "SET LOCAL hnsw.ef_search = 1000"
+}
+
+// renderMigration fills in layout parameters such as the vector dimension
+func renderMigration(file string, spec CollectionSpec) (string, error) {
+	raw, err := postgresMigrations.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(path.Base(file)).Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("migration %s: %w", path.Base(file), err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, spec); err != nil {
+		return "", fmt.Errorf("migration %s: %w", path.Base(file), err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,380 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sentinel-core/internal/domain/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore is a repository.VectorStore on Postgres with the pgvector extension.
+// It mirrors QdrantStore semantics: exact-match payload filters, created_at freshness,
+// cosine score threshold and feedback demotion. The schema is created by Migrate.
+type PostgresStore struct {
+	pool         *pgxpool.Pool
+	searchVector string // Vector column searched by default when document vectors are stored
+	scanSettings string // Per-lookup index scan settings, detected by Migrate
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool, searchVector: entity.QueryVectorName}
+}
+
+// SearchDocumentVectors makes lookups compare against stored document vectors by default
+// (the dual embedding layout with EMBEDDING_SEARCH_VECTOR=document).
+func (s *PostgresStore) SearchDocumentVectors() {
+	s.searchVector = entity.DocumentVectorName
+}
+
+func (s *PostgresStore) Search(ctx context.Context, query entity.SearchQuery) ([]entity.CacheHit, error) {
+	w := &whereBuilder{}
+
+	// 1. Metadata Filters (User ID, intent, provenance)
+	for key, value := range query.Filters {
+		w.filterMatch(key, value)
+	}
+
+	// 2. Freshness Filter (The TTL)
+	if query.MaxAge > 0 {
+		w.add("created_at >= " + w.arg(time.Now().Add(-query.MaxAge)))
+	}
+
+	// 3. Exclude entries users have rated down more than up
+	w.add("COALESCE((payload->>'feedback_score')::bigint, 0) >= 0")
+
+	// 4. Score threshold: cosine distance (<=>) is 1 - similarity
+	column := s.vectorColumn(query.Using)
+	vec := w.arg(vectorLiteral(query.Vector))
+	w.add(fmt.Sprintf("%s IS NOT NULL AND (%s <=> %s::vector) <= %s", column, column, vec, w.arg(1-float64(query.Threshold))))
+
+	sql := fmt.Sprintf(`SELECT id::text, prompt, content, created_at, payload, 1 - (%s <=> %s::vector) AS score
+		FROM semantic_cache %s
+		ORDER BY %s <=> %s::vector
+		LIMIT %d`, column, vec, w.where(), column, vec, max(query.Limit, 1))
+
+	// The scan settings only hold within a transaction (SET LOCAL)
+	var hits []entity.CacheHit
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if s.scanSettings != "" {
+			if _, err := tx.Exec(ctx, s.scanSettings); err != nil {
+				return err
+			}
+		}
+		rows, err := tx.Query(ctx, sql, w.args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var score float64
+			entry, err := scanCacheEntry(rows, &score)
+			if err != nil {
+				return err
+			}
+			hits = append(hits, entity.CacheHit{
+				Response: &entity.AIResponse{
+					ID:      entry.ID,
+					Content: entry.Content,
+					Cached:  true,
+				},
+				Score:     float32(score),
+				Prompt:    entry.Prompt,
+				CreatedAt: entry.CreatedAt,
+				Payload:   entry.Metadata,
+			})
+		}
+		return rows.Err()
+	})
+	return hits, err
+}
+
+const upsertCacheEntrySQL = `INSERT INTO semantic_cache (id, prompt, content, created_at, payload, embedding, document_embedding)
+	VALUES ($1, $2, $3, now(), $4, $5::vector, $6::vector)
+	ON CONFLICT (id) DO UPDATE SET
+		prompt = EXCLUDED.prompt,
+		content = EXCLUDED.content,
+		created_at = EXCLUDED.created_at,
+		payload = EXCLUDED.payload,
+		embedding = EXCLUDED.embedding,
+		document_embedding = EXCLUDED.document_embedding`
+
+func (s *PostgresStore) Save(ctx context.Context, record entity.CacheRecord) error {
+	args, err := upsertArgs(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.Exec(ctx, upsertCacheEntrySQL, args...)
+	return err
+}
+
+// SaveBatch sends all upserts in one pipelined batch inside a transaction
+func (s *PostgresStore) SaveBatch(ctx context.Context, records []entity.CacheRecord) error {
+	batch := &pgx.Batch{}
+	for _, record := range records {
+		args, err := upsertArgs(record)
+		if err != nil {
+			return err
+		}
+		batch.Queue(upsertCacheEntrySQL, args...)
+	}
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+func (s *PostgresStore) Get(ctx context.Context, id string) (*entity.CacheEntry, error) {
+	if uuid.Validate(id) != nil {
+		return nil, entity.ErrResourceNotFound
+	}
+	row := s.pool.QueryRow(ctx, `SELECT id::text, prompt, content, created_at, payload FROM semantic_cache WHERE id = $1`, id)
+	entry, err := scanCacheEntry(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, entity.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List pages through entries in ID order; the cursor is the first ID of the next page.
+func (s *PostgresStore) List(ctx context.Context, filter entity.CacheFilter, limit int, cursor string) (*entity.CachePage, error) {
+	w := adminWhere(filter)
+	if cursor != "" {
+		if uuid.Validate(cursor) != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", entity.ErrInvalidRequest)
+		}
+		w.add("id >= " + w.arg(cursor))
+	}
+
+	// Fetch one extra row to learn where the next page starts
+	sql := fmt.Sprintf(`SELECT id::text, prompt, content, created_at, payload FROM semantic_cache %s ORDER BY id LIMIT %d`, w.where(), limit+1)
+	rows, err := s.pool.Query(ctx, sql, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &entity.CachePage{Entries: []entity.CacheEntry{}}
+	for rows.Next() {
+		entry, err := scanCacheEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Entries) == limit {
+			page.NextCursor = entry.ID
+			break
+		}
+		page.Entries = append(page.Entries, entry)
+	}
+	return page, rows.Err()
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, ids ...string) error {
+	// Malformed IDs cannot exist in the table; drop them instead of failing the cast
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if uuid.Validate(id) == nil {
+			valid = append(valid, id)
+		}
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+	_, err := s.pool.Exec(ctx, `DELETE FROM semantic_cache WHERE id = ANY($1::uuid[])`, valid)
+	return err
+}
+
+func (s *PostgresStore) DeleteByFilter(ctx context.Context, filter entity.CacheFilter) error {
+	if filter.IsEmpty() {
+		return fmt.Errorf("%w: refusing to delete with an empty filter", entity.ErrInvalidRequest)
+	}
+	w := adminWhere(filter)
+	_, err := s.pool.Exec(ctx, "DELETE FROM semantic_cache "+w.where(), w.args...)
+	return err
+}
+
+func (s *PostgresStore) RecordHit(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return entity.ErrResourceNotFound
+	}
+	tag, err := s.pool.Exec(ctx, `UPDATE semantic_cache
+		SET payload = payload || jsonb_build_object(
+			'hit_count', COALESCE((payload->>'hit_count')::bigint, 0) + 1,
+			'last_hit_at', extract(epoch FROM now())::bigint)
+		WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrResourceNotFound
+	}
+	return nil
+}
+
+// ApplyFeedback increments the tally in a single statement, so concurrent votes are not lost.
+func (s *PostgresStore) ApplyFeedback(ctx context.Context, id string, vote entity.Vote) (entity.FeedbackTally, error) {
+	if uuid.Validate(id) != nil {
+		return entity.FeedbackTally{}, entity.ErrResourceNotFound
+	}
+	up, down := vote.Delta()
+
+	var tally entity.FeedbackTally
+	err := s.pool.QueryRow(ctx, `WITH cur AS (
+			SELECT COALESCE((payload->>'feedback_up')::bigint, 0) + $2 AS up,
+			       COALESCE((payload->>'feedback_down')::bigint, 0) + $3 AS down
+			FROM semantic_cache WHERE id = $1 FOR UPDATE
+		)
+		UPDATE semantic_cache s
+		SET payload = s.payload || jsonb_build_object('feedback_up', cur.up, 'feedback_down', cur.down, 'feedback_score', cur.up - cur.down)
+		FROM cur WHERE s.id = $1
+		RETURNING cur.up, cur.down`, id, up, down).Scan(&tally.Up, &tally.Down)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.FeedbackTally{}, entity.ErrResourceNotFound
+	}
+	return tally, err
+}
+
+// --- Private Helpers ---
+
+// vectorColumn picks the column to search; document vectors only exist in the dual layout
+func (s *PostgresStore) vectorColumn(requested string) string {
+	if requested == "" {
+		requested = s.searchVector
+	}
+	if requested == entity.DocumentVectorName {
+		return "document_embedding"
+	}
+	return "embedding"
+}
+
+// adminWhere translates an admin filter; Exclude behaves like Qdrant's must_not,
+// so entries without the field are kept.
+func adminWhere(filter entity.CacheFilter) *whereBuilder {
+	w := &whereBuilder{}
+	if filter.UserID != "" {
+		w.payloadMatch("user_id", filter.UserID, false)
+	}
+	for key, value := range filter.Metadata {
+		w.filterMatch(key, value)
+	}
+	for key, value := range filter.Exclude {
+		w.payloadMatch(key, value, true)
+	}
+	if filter.Text != "" {
+		w.add("to_tsvector('simple', prompt) @@ plainto_tsquery('simple', " + w.arg(filter.Text) + ")")
+	}
+	return w
+}
+
+// whereBuilder accumulates AND-ed conditions and their positional arguments
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+func (w *whereBuilder) arg(v any) string {
+	w.args = append(w.args, v)
+	return "$" + strconv.Itoa(len(w.args))
+}
+
+func (w *whereBuilder) add(cond string) {
+	w.conds = append(w.conds, cond)
+}
+
+// payloadMatch matches a payload field equal to value, or an array field containing it,
+// like Qdrant's keyword match.
+// filterMatch also matches entries cached before the field was recorded
+func (w *whereBuilder) filterMatch(key, value string) {
+	if entity.MatchesUnrecorded(key, value) {
+		scalar, _ := json.Marshal(map[string]any{key: value})
+		w.add(fmt.Sprintf("(payload @> %s::jsonb OR NOT payload ? %s)", w.arg(string(scalar)), w.arg(key)))
+		return
+	}
+	w.payloadMatch(key, value, false)
+}
+
+func (w *whereBuilder) payloadMatch(key, value string, negate bool) {
+	scalar, _ := json.Marshal(map[string]any{key: value})
+	array, _ := json.Marshal(map[string]any{key: []string{value}})
+	cond := fmt.Sprintf("(payload @> %s::jsonb OR payload @> %s::jsonb)", w.arg(string(scalar)), w.arg(string(array)))
+	if negate {
+		cond = "NOT " + cond
+	}
+	w.add(cond)
+}
+
+func (w *whereBuilder) where() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// scanCacheEntry reads id, prompt, content, created_at and payload, then any extra columns
+func scanCacheEntry(row pgx.Row, extra ...any) (entity.CacheEntry, error) {
+	var (
+		entry   entity.CacheEntry
+		payload map[string]any
+	)
+	dest := append([]any{&entry.ID, &entry.Prompt, &entry.Content, &entry.CreatedAt, &payload}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return entity.CacheEntry{}, err
+	}
+
+	entry.Metadata = make(map[string]any, len(payload))
+	for k, v := range payload {
+		if !reservedPayloadKeys[k] {
+			entry.Metadata[k] = v
+		}
+	}
+	return entry, nil
+}
+
+// upsertArgs lays a record out as the arguments of upsertCacheEntrySQL
+func upsertArgs(record entity.CacheRecord) ([]any, error) {
+	metadata := record.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	payload, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reuse the response ID so feedback on the response lands on this entry
+	id := record.Response.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	var document *string
+	if record.DocumentVector != nil {
+		lit := vectorLiteral(record.DocumentVector)
+		document = &lit
+	}
+	return []any{id, record.Prompt, record.Response.Content, payload, vectorLiteral(record.Vector), document}, nil
+}
+
+// vectorLiteral formats a vector in pgvector's text representation, e.g. [0.1,0.2]
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(f), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,272 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// Payload fields owned by the store itself, everything else is caller metadata.
+var reservedPayloadKeys = map[string]bool{
+	"prompt":     true,
+	"content":    true,
+	"created_at": true,
+}
+
+func (s *QdrantStore) Get(ctx context.Context, id string) (*entity.CacheEntry, error) {
+	// Entries are saved under UUIDs; anything else would be a Qdrant error, not a miss
+	if uuid.Validate(id) != nil {
+		return nil, entity.ErrResourceNotFound
+	}
+	points, err := s.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: s.target(),
+		Ids:            []*qdrant.PointId{qdrant.NewID(id)},
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, entity.ErrResourceNotFound
+	}
+
+	entry, err := s.openedEntry(ctx, points[0].Id, points[0].Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *QdrantStore) List(ctx context.Context, filter entity.CacheFilter, limit int, cursor string) (*entity.CachePage, error) {
+	req := &qdrant.ScrollPoints{
+		CollectionName: s.target(),
+		Filter:         buildAdminFilter(filter),
+		Limit:          qdrant.PtrOf(uint32(limit)),
+		WithPayload:    qdrant.NewWithPayload(true),
+	}
+	if len(filter.Fields) > 0 {
+		req.WithPayload = qdrant.NewWithPayloadInclude(filter.Fields...)
+	}
+	if cursor != "" {
+		req.Offset = qdrant.NewID(cursor)
+	}
+
+	points, next, err := s.client.ScrollAndOffset(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.CachePage{Entries: make([]entity.CacheEntry, 0, len(points))}
+	for _, p := range points {
+		entry, err := s.openedEntry(ctx, p.Id, p.Payload)
+		if err != nil {
+			log.Printf("[QDRANT] Skipping undecryptable entry: %v", err)
+			continue
+		}
+		page.Entries = append(page.Entries, entry)
+	}
+	if next != nil {
+		page.NextCursor = pointIDString(next)
+	}
+	return page, nil
+}
+
+func (s *QdrantStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	// Malformed IDs cannot exist in the collection; drop them instead of failing the request
+	pointIDs := make([]*qdrant.PointId, 0, len(ids))
+	for _, id := range ids {
+		if uuid.Validate(id) == nil {
+			pointIDs = append(pointIDs, qdrant.NewID(id))
+		}
+	}
+	if len(pointIDs) == 0 {
+		return nil
+	}
+
+	for _, collection := range s.deleteTargets() {
+		_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+			CollectionName: collection,
+			Points:         qdrant.NewPointsSelectorIDs(pointIDs),
+			Wait:           qdrant.PtrOf(true),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *QdrantStore) DeleteByFilter(ctx context.Context, filter entity.CacheFilter) error {
+	// Refuse to translate an empty filter into "delete the whole collection"
+	if filter.IsEmpty() {
+		return fmt.Errorf("%w: refusing to delete with an empty filter", entity.ErrInvalidRequest)
+	}
+
+	for _, collection := range s.deleteTargets() {
+		_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+			CollectionName: collection,
+			Points:         qdrant.NewPointsSelectorFilter(buildAdminFilter(filter)),
+			Wait:           qdrant.PtrOf(true),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --- Private Helpers ---
+
+func buildAdminFilter(filter entity.CacheFilter) *qdrant.Filter {
+	var must []*qdrant.Condition
+	if filter.UserID != "" {
+		must = append(must, qdrant.NewMatch("user_id", filter.UserID))
+	}
+	for key, value := range filter.Metadata {
+		must = append(must, filterMatch(key, value))
+	}
+	if filter.Text != "" {
+		// Requires the full-text index created in InitCollection
+		must = append(must, qdrant.NewMatchText("prompt", filter.Text))
+	}
+
+	// Entries lacking the field entirely are not excluded
+	var mustNot []*qdrant.Condition
+	for key, value := range filter.Exclude {
+		mustNot = append(mustNot, qdrant.NewMatch(key, value))
+	}
+
+	if len(must) == 0 && len(mustNot) == 0 {
+		return nil
+	}
+	return &qdrant.Filter{Must: must, MustNot: mustNot}
+}
+
+func toCacheEntry(id *qdrant.PointId, payload map[string]*qdrant.Value) entity.CacheEntry {
+	entry := entity.CacheEntry{
+		ID:        pointIDString(id),
+		Prompt:    payload["prompt"].GetStringValue(),
+		Content:   payload["content"].GetStringValue(),
+		CreatedAt: time.Unix(payload["created_at"].GetIntegerValue(), 0),
+		Metadata:  make(map[string]any),
+	}
+	for k, v := range payload {
+		if !reservedPayloadKeys[k] {
+			entry.Metadata[k] = fromQdrantValue(v)
+		}
+	}
+	return entry
+}
+
+func pointIDString(id *qdrant.PointId) string {
+	if id == nil {
+		return ""
+	}
+	if uuid := id.GetUuid(); uuid != "" {
+		return uuid
+	}
+	return fmt.Sprintf("%d", id.GetNum())
+}
+
+// fromQdrantValue is the inverse of qdrant.NewValue
+func fromQdrantValue(v *qdrant.Value) any {
+	switch kind := v.GetKind().(type) {
+	case *qdrant.Value_BoolValue:
+		return kind.BoolValue
+	case *qdrant.Value_IntegerValue:
+		return kind.IntegerValue
+	case *qdrant.Value_DoubleValue:
+		return kind.DoubleValue
+	case *qdrant.Value_StringValue:
+		return kind.StringValue
+	case *qdrant.Value_ListValue:
+		out := make([]any, 0, len(kind.ListValue.GetValues()))
+		for _, item := range kind.ListValue.GetValues() {
+			out = append(out, fromQdrantValue(item))
+		}
+		return out
+	case *qdrant.Value_StructValue:
+		out := make(map[string]any, len(kind.StructValue.GetFields()))
+		for k, item := range kind.StructValue.GetFields() {
+			out[k] = fromQdrantValue(item)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func (s *QdrantStore) RecordHit(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return entity.ErrResourceNotFound
+	}
+	points, err := s.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: s.target(),
+		Ids:            []*qdrant.PointId{qdrant.NewID(id)},
+		WithPayload:    qdrant.NewWithPayloadInclude(entity.HitCountKey),
+	})
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return entity.ErrResourceNotFound
+	}
+
+	// Same read-modify-write as feedback; an undercount under contention only nudges eviction order
+	_, err = s.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+		CollectionName: s.target(),
+		Payload: qdrant.NewValueMap(map[string]any{
+			entity.HitCountKey:  points[0].Payload[entity.HitCountKey].GetIntegerValue() + 1,
+			entity.LastHitAtKey: time.Now().Unix(),
+		}),
+		PointsSelector: qdrant.NewPointsSelector(qdrant.NewID(id)),
+	})
+	return err
+}
+
+func (s *QdrantStore) ApplyFeedback(ctx context.Context, id string, vote entity.Vote) (entity.FeedbackTally, error) {
+	if uuid.Validate(id) != nil {
+		return entity.FeedbackTally{}, entity.ErrResourceNotFound
+	}
+	points, err := s.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: s.target(),
+		Ids:            []*qdrant.PointId{qdrant.NewID(id)},
+		WithPayload:    qdrant.NewWithPayloadInclude("feedback_up", "feedback_down"),
+	})
+	if err != nil {
+		return entity.FeedbackTally{}, err
+	}
+	if len(points) == 0 {
+		return entity.FeedbackTally{}, entity.ErrResourceNotFound
+	}
+
+	// Qdrant has no atomic increment; a lost vote under contention is acceptable here
+	payload := points[0].Payload
+	tally := entity.FeedbackTally{
+		Up:   payload["feedback_up"].GetIntegerValue(),
+		Down: payload["feedback_down"].GetIntegerValue(),
+	}
+	up, down := vote.Delta()
+	tally.Up += up
+	tally.Down += down
+
+	_, err = s.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+		CollectionName: s.target(),
+		Payload: qdrant.NewValueMap(map[string]any{
+			"feedback_up":    tally.Up,
+			"feedback_down":  tally.Down,
+			"feedback_score": tally.Score(),
+		}),
+		PointsSelector: qdrant.NewPointsSelector(qdrant.NewID(id)),
+		Wait:           qdrant.PtrOf(true),
+	})
+	return tally, err
+}
@@ -0,0 +1,515 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sentinel-core/internal/domain/entity"
+	"strconv"
+
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// ErrCollectionMismatch means the existing collection cannot hold vectors from the
+// configured embedder; StartMigration moves the cache to a compatible collection.
+var ErrCollectionMismatch = errors.New("qdrant collection does not match the configured embedder")
+
+// Collection metadata key recording which embedding model filled the collection
+const embeddingModelMetaKey = "embedding_model"
+
+// CollectionSpec is what the cache collection must look like for the configured embedder.
+type CollectionSpec struct {
+	Dimension      uint64
+	EmbeddingModel string
+}
+
+// Reembedder rebuilds the vectors of a cached prompt during a migration.
+type Reembedder func(ctx context.Context, prompt string) (entity.CacheRecord, error)
+
+// activeCollection is the collection (or alias) every operation currently targets.
+type activeCollection struct {
+	name    string
+	sparse  bool   // Has the lexical sparse vector configured
+	filling bool   // Migration target still being re-embedded; lookups miss until it is complete
+	source  string // Collection being re-embedded from; deletes reach it too so they are not copied back
+}
+
+var versionSuffix = regexp.MustCompile(`_v(\d+)$`)
+
+const migrationBatchSize = 64
+
+func (s *QdrantStore) target() string {
+	return s.active.Load().name
+}
+
+// deleteTargets lists the collections a delete must reach. Mid-migration the source goes
+// first: reembedInto then either finds the point gone when it re-checks a copied batch, or
+// the delete has yet to reach the target and removes the copy there.
+func (s *QdrantStore) deleteTargets() []string {
+	active := s.active.Load()
+	if active.source == "" {
+		return []string{active.name}
+	}
+	return []string{active.source, active.name}
+}
+
+// InitCollection makes sure the cache collection exists and matches spec.
+// Fresh installs get a versioned collection ("<name>_v1") behind an alias named
+// after QDRANT_COLLECTION, so later migrations can switch the alias atomically.
+// Collections created before aliases were used are still accepted as-is.
+func (s *QdrantStore) InitCollection(ctx context.Context, spec CollectionSpec) error {
+	physical, _, err := s.resolveCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	if physical == "" {
+		// 1. Create the first versioned collection behind the alias
+		physical = versionedName(s.collectionName, 1)
+		if err := s.createCollection(ctx, physical, spec); err != nil {
+			return err
+		}
+		if err := s.client.CreateAlias(ctx, s.collectionName, physical); err != nil {
+			return fmt.Errorf("failed to create alias %s: %w", s.collectionName, err)
+		}
+		log.Printf("[QDRANT] Created collection %s (dim=%d) behind alias %s", physical, spec.Dimension, s.collectionName)
+	} else {
+		// 2. Validate the existing collection against the embedder
+		info, err := s.client.GetCollectionInfo(ctx, physical)
+		if err != nil {
+			return err
+		}
+		if err := s.validateCollection(info, spec); err != nil {
+			return fmt.Errorf("%w: collection %s: %v", ErrCollectionMismatch, physical, err)
+		}
+	}
+
+	sparse, err := s.hasSparseVector(ctx, physical)
+	if err != nil {
+		return err
+	}
+	if !sparse {
+		log.Printf("[QDRANT] Warning: collection %s has no %q sparse vector; lexical vectors will not be stored", physical, lexicalVectorName)
+	}
+	s.active.Store(&activeCollection{name: s.collectionName, sparse: sparse})
+
+	s.ensureIndexes(ctx, physical)
+	return nil
+}
+
+// StartMigration moves the cache to a new versioned collection built for spec.
+// New answers are written to the new collection immediately; existing entries are
+// re-embedded in the background (when reembed is non-nil) and the alias is then
+// switched over. Lookups miss until then rather than search a half-filled collection.
+// Without reembed the new collection simply starts cold. A migration interrupted by a
+// failure or restart resumes into the same collection. The previous collection is kept
+// for rollback; a legacy one occupying the logical name is first copied to "<name>_v0".
+func (s *QdrantStore) StartMigration(ctx context.Context, spec CollectionSpec, reembed Reembedder) error {
+	old, aliased, err := s.resolveCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	next, err := s.migrationTarget(ctx, old, spec)
+	if err != nil {
+		return err
+	}
+	s.ensureIndexes(ctx, next)
+	active := &activeCollection{name: next, sparse: true}
+	if reembed != nil && old != "" {
+		active.filling, active.source = true, old
+	}
+	s.active.Store(active)
+	log.Printf("[QDRANT] Migration started: %s -> %s (dim=%d, model=%s)", old, next, spec.Dimension, spec.EmbeddingModel)
+
+	go func() {
+		bgCtx := context.WithoutCancel(ctx)
+		if reembed != nil && old != "" {
+			copied, err := s.reembedInto(bgCtx, old, next, reembed)
+			if err != nil {
+				// Lookups keep missing; the next start resumes into the same collection
+				log.Printf("[QDRANT] Migration re-embedding failed after %d entries: %v (restart to resume)", copied, err)
+				return
+			}
+			log.Printf("[QDRANT] Migration re-embedded %d entries into %s", copied, next)
+		}
+
+		kept, err := s.switchAlias(bgCtx, old, aliased, next)
+		if err != nil {
+			log.Printf("[QDRANT] Migration alias switch failed: %v", err)
+			return
+		}
+		s.active.Store(&activeCollection{name: s.collectionName, sparse: true})
+		log.Printf("[QDRANT] Migration complete: alias %s now points at %s", s.collectionName, next)
+		if kept != "" {
+			log.Printf("[QDRANT] Previous collection kept for rollback as %s; delete it once satisfied", kept)
+		}
+	}()
+	return nil
+}
+
+// --- Private Helpers ---
+
+// resolveCollection finds the physical collection behind the logical name: the alias
+// target (aliased=true), a legacy collection of that exact name, or "" when neither exists.
+func (s *QdrantStore) resolveCollection(ctx context.Context) (string, bool, error) {
+	aliases, err := s.client.ListAliases(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	for _, a := range aliases {
+		if a.GetAliasName() == s.collectionName {
+			return a.GetCollectionName(), true, nil
+		}
+	}
+
+	exists, err := s.client.CollectionExists(ctx, s.collectionName)
+	if err != nil || !exists {
+		return "", false, err
+	}
+	return s.collectionName, false, nil
+}
+
+// migrationTarget picks the collection to migrate into: the one an interrupted migration
+// left behind when it matches spec (re-embedding upserts by ID, so copying again is safe),
+// otherwise the next version not taken yet.
+func (s *QdrantStore) migrationTarget(ctx context.Context, old string, spec CollectionSpec) (string, error) {
+	for version := collectionVersion(old) + 1; ; version++ {
+		name := versionedName(s.collectionName, version)
+		exists, err := s.client.CollectionExists(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return name, s.createCollection(ctx, name, spec)
+		}
+		info, err := s.client.GetCollectionInfo(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		if err := s.validateCollection(info, spec); err != nil {
+			log.Printf("[QDRANT] Skipping leftover collection %s: %v", name, err)
+			continue
+		}
+		log.Printf("[QDRANT] Resuming interrupted migration into %s", name)
+		return name, nil
+	}
+}
+
+// createCollection creates a dense + IDF-weighted lexical sparse vector collection
+func (s *QdrantStore) createCollection(ctx context.Context, name string, spec CollectionSpec) error {
+	err := s.client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: name,
+		VectorsConfig:  s.vectorsConfig(spec.Dimension),
+		SparseVectorsConfig: qdrant.NewSparseVectorsConfig(map[string]*qdrant.SparseVectorParams{
+			lexicalVectorName: {Modifier: qdrant.Modifier_Idf.Enum()},
+		}),
+		Metadata: qdrant.NewValueMap(map[string]any{
+			embeddingModelMetaKey: spec.EmbeddingModel,
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create collection %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *QdrantStore) vectorsConfig(dim uint64) *qdrant.VectorsConfig {
+	params := &qdrant.VectorParams{
+		Size:     dim,
+		Distance: qdrant.Distance_Cosine,
+	}
+	if !s.dualVectors {
+		return qdrant.NewVectorsConfig(params)
+	}
+	return qdrant.NewVectorsConfigMap(map[string]*qdrant.VectorParams{
+		entity.QueryVectorName:    params,
+		entity.DocumentVectorName: params,
+	})
+}
+
+// validateCollection checks layout, dimension, distance and (when recorded) the embedding model
+func (s *QdrantStore) validateCollection(info *qdrant.CollectionInfo, spec CollectionSpec) error {
+	config := info.GetConfig()
+	vectors := config.GetParams().GetVectorsConfig()
+
+	var params []*qdrant.VectorParams
+	if s.dualVectors {
+		named := vectors.GetParamsMap().GetMap()
+		for _, name := range []string{entity.QueryVectorName, entity.DocumentVectorName} {
+			p, ok := named[name]
+			if !ok {
+				return fmt.Errorf("missing named vector %q required by the dual embedding mode", name)
+			}
+			params = append(params, p)
+		}
+	} else {
+		if vectors.GetParams() == nil {
+			return fmt.Errorf("expected a single unnamed vector, found named vectors")
+		}
+		params = append(params, vectors.GetParams())
+	}
+
+	for _, p := range params {
+		if p.GetSize() != spec.Dimension {
+			return fmt.Errorf("vector size is %d but the embedder produces %d", p.GetSize(), spec.Dimension)
+		}
+		if p.GetDistance() != qdrant.Distance_Cosine {
+			return fmt.Errorf("distance is %s, expected %s", p.GetDistance(), qdrant.Distance_Cosine)
+		}
+	}
+
+	if model := config.GetMetadata()[embeddingModelMetaKey].GetStringValue(); model != "" && spec.EmbeddingModel != "" && model != spec.EmbeddingModel {
+		return fmt.Errorf("filled by embedding model %s, configured model is %s", model, spec.EmbeddingModel)
+	}
+	return nil
+}
+
+func (s *QdrantStore) hasSparseVector(ctx context.Context, name string) (bool, error) {
+	info, err := s.client.GetCollectionInfo(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	_, ok := info.GetConfig().GetParams().GetSparseVectorsConfig().GetMap()[lexicalVectorName]
+	return ok, nil
+}
+
+func (s *QdrantStore) ensureIndexes(ctx context.Context, collection string) {
+	// 1. The Freshness Filter (TTL): makes range queries on "created_at" lightning fast
+	s.ensureFieldIndex(ctx, collection, "created_at", qdrant.FieldType_FieldTypeInteger)
+
+	// 2. Indexes used by the admin API (scope lookups and full-text search on prompts)
+	s.ensureFieldIndex(ctx, collection, "user_id", qdrant.FieldType_FieldTypeKeyword)
+	s.ensureFieldIndex(ctx, collection, "prompt", qdrant.FieldType_FieldTypeText)
+	s.ensureFieldIndex(ctx, collection, "feedback_score", qdrant.FieldType_FieldTypeInteger)
+
+	// 3. Tenant isolation: every lookup filters on the tenant, so Qdrant co-locates each tenant's points
+	_, err := s.client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+		CollectionName:   collection,
+		FieldName:        entity.TenantIDKey,
+		FieldType:        qdrant.FieldType_FieldTypeKeyword.Enum(),
+		FieldIndexParams: qdrant.NewPayloadIndexParamsKeyword(&qdrant.KeywordIndexParams{IsTenant: qdrant.PtrOf(true)}),
+		Wait:             qdrant.PtrOf(true),
+	})
+	if err != nil {
+		log.Printf("[QDRANT] Warning: Could not create %s index (might already exist): %v", entity.TenantIDKey, err)
+	}
+
+	// 4. Indexes for the model/template compatibility filters
+	for _, field := range []string{entity.ModelKey, entity.ModelFamilyKey, entity.OptionsHashKey, entity.TemplateVersionKey} {
+		s.ensureFieldIndex(ctx, collection, field, qdrant.FieldType_FieldTypeKeyword)
+	}
+}
+
+// ensureFieldIndex uses the 'Wait' flag so the index is ready before we start serving
+func (s *QdrantStore) ensureFieldIndex(ctx context.Context, collection, field string, fieldType qdrant.FieldType) {
+	_, err := s.client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+		CollectionName: collection,
+		FieldName:      field,
+		FieldType:      fieldType.Enum(),
+		Wait:           qdrant.PtrOf(true),
+	})
+	if err != nil {
+		// Log but don't fail if index already exists
+		log.Printf("[QDRANT] Warning: Could not create %s index (might already exist): %v", field, err)
+	}
+}
+
+// reembedInto copies every entry of src into dst with freshly computed vectors, keeping payloads
+func (s *QdrantStore) reembedInto(ctx context.Context, src, dst string, reembed Reembedder) (int, error) {
+	copied := 0
+	var offset *qdrant.PointId
+	for {
+		points, next, err := s.client.ScrollAndOffset(ctx, &qdrant.ScrollPoints{
+			CollectionName: src,
+			Offset:         offset,
+			Limit:          qdrant.PtrOf(uint32(migrationBatchSize)),
+			WithPayload:    qdrant.NewWithPayload(true),
+		})
+		if err != nil {
+			return copied, err
+		}
+
+		batch := make([]*qdrant.PointStruct, 0, len(points))
+		for _, p := range points {
+			// Payloads are copied as stored; only the prompt is opened to embed it
+			entry, err := s.openedEntry(ctx, p.Id, p.Payload)
+			if err != nil {
+				return copied, err
+			}
+			record, err := reembed(ctx, entry.Prompt)
+			if err != nil {
+				return copied, err
+			}
+			batch = append(batch, &qdrant.PointStruct{
+				Id:      p.Id,
+				Vectors: s.pointVectors(record),
+				Payload: p.Payload,
+			})
+		}
+		if len(batch) > 0 {
+			if _, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{CollectionName: dst, Points: batch}); err != nil {
+				return copied, err
+			}
+			if err := s.dropDeletedCopies(ctx, src, dst, batch); err != nil {
+				return copied, err
+			}
+			copied += len(batch)
+			log.Printf("[QDRANT] Migration progress: %d entries re-embedded", copied)
+		}
+
+		if next == nil {
+			return copied, nil
+		}
+		offset = next
+	}
+}
+
+// dropDeletedCopies removes copies of points deleted from src while their batch was being
+// re-embedded, so a purge that ran mid-migration does not come back with the new collection
+func (s *QdrantStore) dropDeletedCopies(ctx context.Context, src, dst string, batch []*qdrant.PointStruct) error {
+	ids := make([]*qdrant.PointId, len(batch))
+	for i, p := range batch {
+		ids[i] = p.Id
+	}
+	remaining, err := s.client.Get(ctx, &qdrant.GetPoints{CollectionName: src, Ids: ids})
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(remaining))
+	for _, p := range remaining {
+		present[pointIDString(p.Id)] = true
+	}
+
+	var gone []*qdrant.PointId
+	for _, id := range ids {
+		if !present[pointIDString(id)] {
+			gone = append(gone, id)
+		}
+	}
+	if len(gone) == 0 {
+		return nil
+	}
+	_, err = s.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: dst,
+		Points:         qdrant.NewPointsSelectorIDs(gone),
+		Wait:           qdrant.PtrOf(true),
+	})
+	return err
+}
+
+// switchAlias points the logical name at next and returns the collection kept for rollback.
+// Aliased setups switch atomically; a legacy collection occupying the name is copied aside
+// and dropped first, leaving a brief window of misses.
+func (s *QdrantStore) switchAlias(ctx context.Context, old string, aliased bool, next string) (string, error) {
+	if aliased {
+		return old, s.client.UpdateAliases(ctx, []*qdrant.AliasOperations{
+			qdrant.NewAliasDelete(s.collectionName),
+			qdrant.NewAliasCreate(s.collectionName, next),
+		})
+	}
+	var kept string
+	if old != "" {
+		kept = versionedName(s.collectionName, 0)
+		if err := s.copyCollection(ctx, old, kept); err != nil {
+			return "", fmt.Errorf("failed to keep %s as %s: %w", old, kept, err)
+		}
+		if err := s.client.DeleteCollection(ctx, old); err != nil {
+			return "", err
+		}
+	}
+	return kept, s.client.CreateAlias(ctx, s.collectionName, next)
+}
+
+// copyCollection copies src verbatim, vectors included, into dst created with src's layout.
+// An existing dst is a copy an interrupted switch left behind; upserting by ID completes it.
+func (s *QdrantStore) copyCollection(ctx context.Context, src, dst string) error {
+	exists, err := s.client.CollectionExists(ctx, dst)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		info, err := s.client.GetCollectionInfo(ctx, src)
+		if err != nil {
+			return err
+		}
+		err = s.client.CreateCollection(ctx, &qdrant.CreateCollection{
+			CollectionName:      dst,
+			VectorsConfig:       info.GetConfig().GetParams().GetVectorsConfig(),
+			SparseVectorsConfig: info.GetConfig().GetParams().GetSparseVectorsConfig(),
+			Metadata:            info.GetConfig().GetMetadata(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var offset *qdrant.PointId
+	for {
+		points, next, err := s.client.ScrollAndOffset(ctx, &qdrant.ScrollPoints{
+			CollectionName: src,
+			Offset:         offset,
+			Limit:          qdrant.PtrOf(uint32(migrationBatchSize)),
+			WithPayload:    qdrant.NewWithPayload(true),
+			WithVectors:    qdrant.NewWithVectors(true),
+		})
+		if err != nil {
+			return err
+		}
+		batch := make([]*qdrant.PointStruct, len(points))
+		for i, p := range points {
+			batch[i] = &qdrant.PointStruct{Id: p.Id, Vectors: vectorsInput(p.Vectors), Payload: p.Payload}
+		}
+		if len(batch) > 0 {
+			if _, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{CollectionName: dst, Points: batch, Wait: qdrant.PtrOf(true)}); err != nil {
+				return err
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		offset = next
+	}
+}
+
+// vectorsInput turns stored vectors back into the form upserts take
+func vectorsInput(v *qdrant.VectorsOutput) *qdrant.Vectors {
+	if named := v.GetVectors().GetVectors(); named != nil {
+		vectors := make(map[string]*qdrant.Vector, len(named))
+		for name, vec := range named {
+			vectors[name] = vectorInput(vec)
+		}
+		return qdrant.NewVectorsMap(vectors)
+	}
+	return &qdrant.Vectors{VectorsOptions: &qdrant.Vectors_Vector{Vector: vectorInput(v.GetVector())}}
+}
+
+func vectorInput(v *qdrant.VectorOutput) *qdrant.Vector {
+	if sparse := v.GetSparse(); sparse != nil {
+		return qdrant.NewVectorSparse(sparse.GetIndices(), sparse.GetValues())
+	}
+	if dense := v.GetDense(); dense != nil {
+		return qdrant.NewVectorDense(dense.GetData())
+	}
+	// Older servers only fill the deprecated flat fields
+	if indices := v.GetIndices(); indices != nil {
+		return qdrant.NewVectorSparse(indices.GetData(), v.GetData())
+	}
+	return qdrant.NewVectorDense(v.GetData())
+}
+
+func versionedName(base string, version int) string {
+	return fmt.Sprintf("%s_v%d", base, version)
+}
+
+// collectionVersion parses "<name>_v<N>"; legacy and missing collections are version 0
+func collectionVersion(name string) int {
+	m := versionSuffix.FindStringSubmatch(name)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
@@ -0,0 +1,211 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// Payload fields holding conversation text, sealed when a cipher is configured. The
+// lexical signature lists are sealed as JSON, they quote numbers and names from the prompt.
+var (
+	sealedTextFields = []string{"prompt", "content"}
+	sealedListFields = []string{entity.LexNumbersKey, entity.LexEntitiesKey, entity.LexRelationsKey, entity.LexTermsKey}
+)
+
+// WithPayloadCipher encrypts prompts, answers and lexical signatures at rest with the
+// entry's tenant keys. Full-text admin search on prompts no longer matches sealed entries.
+//
+// Lookups filter and rank on the remaining fields, so they stay in plaintext: the
+// extractor's intent metadata (action, source and target values taken from the prompt)
+// and the lexical sparse vector, whose indices are unkeyed term hashes. Together they
+// reveal much of a prompt; the sealing only protects the full text.
+func WithPayloadCipher(c repository.PayloadCipher) QdrantOption {
+	return func(s *QdrantStore) {
+		s.cipher = c
+	}
+}
+
+// Reencrypt seals every entry whose fields are in plaintext or sealed with a retired
+// data key version, so old keys can be dropped after a rotation. An entry rewritten
+// while it was being sealed (a background refresh) is left alone: that write already
+// sealed it with the current key, and writing back what was read would revert it.
+func (s *QdrantStore) Reencrypt(ctx context.Context) (entity.ReencryptionSummary, error) {
+	var summary entity.ReencryptionSummary
+	if s.cipher == nil {
+		return summary, nil
+	}
+	fields := append(append([]string{entity.TenantIDKey, "created_at"}, sealedTextFields...), sealedListFields...)
+
+	var offset *qdrant.PointId
+	for {
+		points, next, err := s.client.ScrollAndOffset(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.target(),
+			Offset:         offset,
+			Limit:          qdrant.PtrOf(uint32(migrationBatchSize)),
+			WithPayload:    qdrant.NewWithPayloadInclude(fields...),
+		})
+		if err != nil {
+			return summary, err
+		}
+
+		for _, p := range points {
+			summary.Scanned++
+			stale, err := s.openPayload(ctx, p.Payload)
+			if err != nil {
+				summary.Failed++
+				log.Printf("[QDRANT] Cannot re-encrypt %s: %v", pointIDString(p.Id), err)
+				continue
+			}
+			if !stale {
+				continue
+			}
+			payload := make(map[string]any, len(p.Payload))
+			for k, v := range p.Payload {
+				payload[k] = fromQdrantValue(v)
+			}
+			if err := s.sealPayload(ctx, payload); err != nil {
+				return summary, err
+			}
+			delete(payload, entity.TenantIDKey)
+			delete(payload, "created_at")
+
+			// Re-read just before writing, the wider the window the likelier a refresh lands in it
+			unchanged, err := s.createdAtIs(ctx, p.Id, p.Payload["created_at"].GetIntegerValue())
+			if err != nil {
+				return summary, err
+			}
+			if !unchanged {
+				continue
+			}
+			_, err = s.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+				CollectionName: s.target(),
+				Payload:        qdrant.NewValueMap(payload),
+				PointsSelector: qdrant.NewPointsSelector(p.Id),
+			})
+			if err != nil {
+				return summary, err
+			}
+			summary.Reencrypted++
+		}
+
+		if next == nil {
+			return summary, nil
+		}
+		offset = next
+	}
+}
+
+// --- Private Helpers ---
+
+// createdAtIs reports whether a point still exists with the given created_at, i.e. it
+// has not been rewritten or deleted since it was read
+func (s *QdrantStore) createdAtIs(ctx context.Context, id *qdrant.PointId, createdAt int64) (bool, error) {
+	points, err := s.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: s.target(),
+		Ids:            []*qdrant.PointId{id},
+		WithPayload:    qdrant.NewWithPayloadInclude("created_at"),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(points) == 1 && points[0].Payload["created_at"].GetIntegerValue() == createdAt, nil
+}
+
+// sealPayload replaces the sensitive fields of a payload about to be written with
+// their sealed form
+func (s *QdrantStore) sealPayload(ctx context.Context, payload map[string]any) error {
+	if s.cipher == nil {
+		return nil
+	}
+	tenant, _ := payload[entity.TenantIDKey].(string)
+	for _, field := range sealedTextFields {
+		text, ok := payload[field].(string)
+		if !ok {
+			continue
+		}
+		sealed, err := s.cipher.Seal(ctx, tenant, []byte(text))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", field, err)
+		}
+		payload[field] = sealed
+	}
+	for _, field := range sealedListFields {
+		list, ok := payload[field].([]any)
+		if !ok {
+			continue
+		}
+		raw, err := json.Marshal(list)
+		if err != nil {
+			return err
+		}
+		sealed, err := s.cipher.Seal(ctx, tenant, raw)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", field, err)
+		}
+		payload[field] = sealed
+	}
+	return nil
+}
+
+// openPayload decrypts the sealed fields of a payload read from Qdrant in place. stale
+// reports fields that are in plaintext or sealed with a retired key.
+func (s *QdrantStore) openPayload(ctx context.Context, payload map[string]*qdrant.Value) (bool, error) {
+	if s.cipher == nil {
+		return false, nil
+	}
+	tenant := payload[entity.TenantIDKey].GetStringValue()
+	stale := false
+	for _, field := range sealedTextFields {
+		v, ok := payload[field]
+		if !ok {
+			continue
+		}
+		plain, old, err := s.cipher.Open(ctx, tenant, v.GetStringValue())
+		if err != nil {
+			return false, err
+		}
+		payload[field] = qdrant.NewValueString(string(plain))
+		stale = stale || old
+	}
+	for _, field := range sealedListFields {
+		v, ok := payload[field]
+		if !ok {
+			continue
+		}
+		if _, isText := v.GetKind().(*qdrant.Value_StringValue); !isText {
+			stale = true // A list cached before encryption was enabled
+			continue
+		}
+		plain, old, err := s.cipher.Open(ctx, tenant, v.GetStringValue())
+		if err != nil {
+			return false, err
+		}
+		var list []any
+		if err := json.Unmarshal(plain, &list); err != nil {
+			return false, fmt.Errorf("corrupt %s: %w", field, err)
+		}
+		opened, err := qdrant.NewValue(list)
+		if err != nil {
+			return false, err
+		}
+		payload[field] = opened
+		stale = stale || old
+	}
+	return stale, nil
+}
+
+// openedEntry decrypts a point's payload into a cache entry, leaving the point untouched
+func (s *QdrantStore) openedEntry(ctx context.Context, id *qdrant.PointId, payload map[string]*qdrant.Value) (entity.CacheEntry, error) {
+	payload = maps.Clone(payload)
+	if _, err := s.openPayload(ctx, payload); err != nil {
+		return entity.CacheEntry{}, fmt.Errorf("entry %s: %w", pointIDString(id), err)
+	}
+	return toCacheEntry(id, payload), nil
+}
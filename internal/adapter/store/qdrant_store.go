@@ -2,139 +2,215 @@ package store
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/qdrant/go-client/qdrant"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
+// Name of the sparse vector holding the lexical (BM25-style) representation
+const lexicalVectorName = "lexical"
+
 type QdrantStore struct {
 	client         *qdrant.Client
-	collectionName string
+	collectionName string // Logical name; an alias over versioned collections (see qdrant_collection.go)
+	active         atomic.Pointer[activeCollection]
+
+	// Dual layout: named "query" and "document" dense vectors instead of one unnamed vector
+	dualVectors  bool
+	searchVector string // Named vector searched by default in the dual layout
+
+	cipher repository.PayloadCipher // Seals conversation text at rest when set (see qdrant_encryption.go)
 }
 
-func NewQdrantStore(client *qdrant.Client, collectionName string) *QdrantStore {
-	return &QdrantStore{
-		client:         client,
-		collectionName: collectionName,
+type QdrantOption func(*QdrantStore)
+
+// WithDualVectors stores query and document embeddings as named vectors and
+// searches the given one unless a query asks for the other.
+func WithDualVectors(searchVector string) QdrantOption {
+	return func(s *QdrantStore) {
+		s.dualVectors = true
+		s.searchVector = searchVector
 	}
 }
 
-func (s *QdrantStore) InitCollection(ctx context.Context, dim uint64) error {
-	_, err := s.client.GetCollectionInfo(ctx, s.collectionName)
-	if err != nil {
-		st, ok := status.FromError(err)
-		if ok && st.Code() == codes.NotFound {
-			// 1. Create the Collection
-			err := s.client.CreateCollection(ctx, &qdrant.CreateCollection{
-				CollectionName: s.collectionName,
-				VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-					Size:     dim,
-					Distance: qdrant.Distance_Cosine,
-				}),
-			})
-			if err != nil {
-				return fmt.Errorf("failed to create collection: %w", err)
-			}
-		} else {
-			return err
-		}
+func NewQdrantStore(client *qdrant.Client, collectionName string, opts ...QdrantOption) *QdrantStore {
+	s := &QdrantStore{
+		client:         client,
+		collectionName: collectionName,
 	}
-
-	// 2. Create the Payload Index for the Freshness Filter (TTL)
-	// This makes range queries on "created_at" lightning fast.
-	// We use the 'Wait' flag to ensure the index is ready before we start.
-	_, err = s.client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
-		CollectionName: s.collectionName,
-		FieldName:      "created_at",
-		FieldType:      qdrant.FieldType_FieldTypeInteger.Enum(),
-		Wait:           qdrant.PtrOf(true),
-	})
-
-	if err != nil {
-		// Log but don't fail if index already exists
-		log.Printf("[QDRANT] Warning: Could not create created_at index (might already exist): %v", err)
+	for _, opt := range opts {
+		opt(s)
 	}
-
-	return nil
+	s.active.Store(&activeCollection{name: collectionName})
+	return s
 }
 
-func (s *QdrantStore) Search(ctx context.Context, vector []float32, threshold float32, filters map[string]string) (*entity.AIResponse, float32, string, error) { // 1. Construct the Filter
+func (s *QdrantStore) Search(ctx context.Context, query entity.SearchQuery) ([]entity.CacheHit, error) { // 1. Construct the Filter
+	if s.active.Load().filling {
+		return nil, nil // Mid-migration: the new collection is incomplete until re-embedding finishes
+	}
+
 	var mustConditions []*qdrant.Condition
 
 	// 1. Add Existing Metadata Filters (User ID, Source, etc.)
-	for key, value := range filters {
-		mustConditions = append(mustConditions, qdrant.NewMatch(key, value))
+	for key, value := range query.Filters {
+		mustConditions = append(mustConditions, filterMatch(key, value))
 	}
 
 	// 2. Add Freshness Filter (The TTL)
-	// Only return results created in the last 24 hours
-	oneDayAgo := time.Now().Add(-24 * time.Hour).Unix()
-	mustConditions = append(mustConditions, &qdrant.Condition{
-		ConditionOneOf: &qdrant.Condition_Field{
-			Field: &qdrant.FieldCondition{
-				Key: "created_at",
-				Range: &qdrant.Range{
-					Gte: qdrant.PtrOf(float64(oneDayAgo)), // Greater than or equal to
+	// Only return results created within the caller's max age
+	if query.MaxAge > 0 {
+		oldest := time.Now().Add(-query.MaxAge).Unix()
+		mustConditions = append(mustConditions, &qdrant.Condition{
+			ConditionOneOf: &qdrant.Condition_Field{
+				Field: &qdrant.FieldCondition{
+					Key: "created_at",
+					Range: &qdrant.Range{
+						Gte: qdrant.PtrOf(float64(oldest)), // Greater than or equal to
+					},
 				},
 			},
-		},
-	})
+		})
+	}
+
+	// 3. Exclude entries users have rated down more than up
+	mustNotConditions := []*qdrant.Condition{
+		qdrant.NewRange("feedback_score", &qdrant.Range{Lt: qdrant.PtrOf(float64(0))}),
+	}
 
 	res, err := s.client.Query(ctx, &qdrant.QueryPoints{
-		CollectionName: s.collectionName,
-		Query:          qdrant.NewQuery(vector...),
-		Filter:         &qdrant.Filter{Must: mustConditions},
-		Limit:          qdrant.PtrOf(uint64(1)),
+		CollectionName: s.target(),
+		Query:          qdrant.NewQuery(query.Vector...),
+		Filter:         &qdrant.Filter{Must: mustConditions, MustNot: mustNotConditions},
+		Limit:          qdrant.PtrOf(uint64(max(query.Limit, 1))),
 		WithPayload:    qdrant.NewWithPayload(true),
-		ScoreThreshold: &threshold,
+		ScoreThreshold: &query.Threshold,
+		Using:          s.using(query.Using),
 	})
 
-	if err != nil || len(res) == 0 {
-		return nil, 0, "", err
+	if err != nil {
+		return nil, err
 	}
 
-	hit := res[0]
-	payload := hit.Payload
+	// 4. Extract data, keeping Qdrant's score ordering
+	hits := make([]entity.CacheHit, 0, len(res))
+	for _, point := range res {
+		entry, err := s.openedEntry(ctx, point.Id, point.Payload)
+		if err != nil {
+			log.Printf("[QDRANT] Skipping undecryptable hit: %v", err)
+			continue
+		}
+		hits = append(hits, entity.CacheHit{
+			Response: &entity.AIResponse{
+				ID:      entry.ID,
+				Content: entry.Content,
+				Cached:  true,
+			},
+			Score:     point.Score,
+			Prompt:    entry.Prompt,
+			CreatedAt: entry.CreatedAt,
+			Payload:   entry.Metadata,
+		})
+	}
+
+	return hits, nil
+}
 
-	// 3. Extract data
-	originalPrompt := payload["prompt"].GetStringValue()
-	content := payload["content"].GetStringValue()
+func (s *QdrantStore) Save(ctx context.Context, record entity.CacheRecord) error {
+	return s.SaveBatch(ctx, []entity.CacheRecord{record})
+}
 
-	response := &entity.AIResponse{
-		Content: content,
-		Cached:  true,
+func (s *QdrantStore) SaveBatch(ctx context.Context, records []entity.CacheRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	points := make([]*qdrant.PointStruct, len(records))
+	for i, record := range records {
+		point, err := s.point(ctx, record)
+		if err != nil {
+			return err
+		}
+		points[i] = point
 	}
 
-	return response, hit.Score, originalPrompt, nil
+	_, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: s.target(),
+		Points:         points,
+	})
+	return err
 }
 
-func (s *QdrantStore) Save(ctx context.Context, prompt string, resp *entity.AIResponse, vector []float32, metadata map[string]any) error { // Prepare base payload
+// point builds the Qdrant point for a record
+func (s *QdrantStore) point(ctx context.Context, record entity.CacheRecord) (*qdrant.PointStruct, error) { // Prepare base payload
 	payload := map[string]any{
-		"prompt":     prompt,
-		"content":    resp.Content,
+		"prompt":     record.Prompt,
+		"content":    record.Response.Content,
 		"created_at": time.Now().Unix(), // Store as Unix integer
 	}
 
-	// Merge in extra metadata (e.g., user_id, source_account)
-	for k, v := range metadata {
+	// Merge in extra metadata (e.g., user_id, source_account, lexical signature)
+	for k, v := range record.Metadata {
 		payload[k] = v
 	}
 
-	_, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{
-		CollectionName: s.collectionName,
-		Points: []*qdrant.PointStruct{
-			{
-				Id:      qdrant.NewIDUUID(uuid.NewString()),
-				Vectors: qdrant.NewVectors(vector...),
-				Payload: qdrant.NewValueMap(payload),
-			},
-		},
-	})
-	return err
+	if err := s.sealPayload(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	// Reuse the response ID so feedback on the response lands on this entry
+	id := record.Response.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	return &qdrant.PointStruct{
+		Id:      qdrant.NewIDUUID(id),
+		Vectors: s.pointVectors(record),
+		Payload: qdrant.NewValueMap(payload),
+	}, nil
+}
+
+// pointVectors lays the record's vectors out to match the collection
+func (s *QdrantStore) pointVectors(record entity.CacheRecord) *qdrant.Vectors {
+	named := make(map[string]*qdrant.Vector)
+	if s.dualVectors {
+		named[entity.QueryVectorName] = qdrant.NewVectorDense(record.Vector)
+		named[entity.DocumentVectorName] = qdrant.NewVectorDense(record.DocumentVector)
+	} else {
+		named[""] = qdrant.NewVectorDense(record.Vector)
+	}
+	if s.active.Load().sparse && record.Sparse != nil {
+		named[lexicalVectorName] = qdrant.NewVectorSparse(record.Sparse.Indices, record.Sparse.Values)
+	}
+
+	if len(named) == 1 && !s.dualVectors {
+		return qdrant.NewVectors(record.Vector...)
+	}
+	return qdrant.NewVectorsMap(named)
+}
+
+// using picks the named vector to search; nil means the unnamed default vector
+func (s *QdrantStore) using(requested string) *string {
+	if !s.dualVectors {
+		return nil
+	}
+	if requested == "" {
+		requested = s.searchVector
+	}
+	return &requested
+}
+
+// filterMatch also matches entries cached before the field was recorded
+func filterMatch(key, value string) *qdrant.Condition {
+	if entity.MatchesUnrecorded(key, value) {
+		return qdrant.NewFilterAsCondition(&qdrant.Filter{
+			Should: []*qdrant.Condition{qdrant.NewMatch(key, value), qdrant.NewIsEmpty(key)},
+		})
+	}
+	return qdrant.NewMatch(key, value)
 }
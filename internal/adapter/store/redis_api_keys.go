@@ -0,0 +1,192 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sentinel-core/internal/domain/entity"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisAPIKeyStore keeps each key as a JSON document under apikey:<id>, indexed by
+// apikey:hash:<sha256>. The index of a rotated-out secret expires with its grace period.
+type RedisAPIKeyStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// Optimistic transactions retried when another save touched the key in between
+const maxSaveAttempts = 5
+
+func NewRedisAPIKeyStore(client *redis.Client) *RedisAPIKeyStore {
+	return &RedisAPIKeyStore{client: client, prefix: "apikey"}
+}
+
+// storedAPIKey also serializes the hashes entity.APIKey keeps out of API responses
+type storedAPIKey struct {
+	entity.APIKey
+	Hash         string `json:"hash"`
+	PreviousHash string `json:"previous_hash,omitempty"`
+}
+
+func (r *RedisAPIKeyStore) Save(ctx context.Context, key entity.APIKey) error {
+	_, err := r.transact(ctx, key.ID, func(*entity.APIKey) (*entity.APIKey, error) {
+		return &key, nil
+	})
+	return err
+}
+
+func (r *RedisAPIKeyStore) Update(ctx context.Context, id string, fn func(*entity.APIKey) error) (*entity.APIKey, error) {
+	return r.transact(ctx, id, func(old *entity.APIKey) (*entity.APIKey, error) {
+		if old == nil {
+			return nil, entity.ErrResourceNotFound
+		}
+		key := *old
+		if err := fn(&key); err != nil {
+			return nil, err
+		}
+		return &key, nil
+	})
+}
+
+func (r *RedisAPIKeyStore) Get(ctx context.Context, id string) (*entity.APIKey, error) {
+	raw, err := r.client.Get(ctx, r.idKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, entity.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeAPIKey(raw)
+}
+
+func (r *RedisAPIKeyStore) FindByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	id, err := r.client.Get(ctx, r.hashKey(hash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, entity.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *RedisAPIKeyStore) List(ctx context.Context, owner string) ([]entity.APIKey, error) {
+	ids, err := r.client.SMembers(ctx, r.prefix+":ids").Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = r.idKey(id)
+	}
+	docs, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []entity.APIKey
+	for _, doc := range docs {
+		raw, ok := doc.(string)
+		if !ok {
+			continue
+		}
+		key, err := decodeAPIKey([]byte(raw))
+		if err != nil {
+			return nil, err
+		}
+		if owner == "" || key.Owner == owner {
+			out = append(out, *key)
+		}
+	}
+	slices.SortFunc(out, func(a, b entity.APIKey) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return out, nil
+}
+
+// --- Private Helpers ---
+
+// transact replaces a key with what next makes of the stored one (nil when missing). The
+// document is WATCHed from the read to the write, so concurrent changes (two rotations, a
+// rotation and a revocation) cannot both build on the same state or leave an index entry
+// behind; the loser retries against the winner's state.
+func (r *RedisAPIKeyStore) transact(ctx context.Context, id string, next func(old *entity.APIKey) (*entity.APIKey, error)) (*entity.APIKey, error) {
+	var saved *entity.APIKey
+	var err, rejected error
+	for attempt := 0; attempt < maxSaveAttempts; attempt++ {
+		err = r.client.Watch(ctx, func(tx *redis.Tx) error {
+			// 1. The stored key, and with it the hashes it is indexed under
+			var old *entity.APIKey
+			prev, err := tx.Get(ctx, r.idKey(id)).Bytes()
+			switch {
+			case err == nil:
+				if old, err = decodeAPIKey(prev); err != nil {
+					return err
+				}
+			case !errors.Is(err, redis.Nil):
+				return err
+			}
+
+			var key *entity.APIKey
+			if key, rejected = next(old); rejected != nil {
+				return rejected
+			}
+			raw, err := json.Marshal(storedAPIKey{APIKey: *key, Hash: key.Hash, PreviousHash: key.PreviousHash})
+			if err != nil {
+				return err
+			}
+
+			// 2. Swap document and indexes atomically
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				if old != nil {
+					pipe.Del(ctx, r.hashKey(old.Hash))
+					if old.PreviousHash != "" {
+						pipe.Del(ctx, r.hashKey(old.PreviousHash))
+					}
+				}
+				pipe.Set(ctx, r.idKey(key.ID), raw, 0)
+				pipe.SAdd(ctx, r.prefix+":ids", key.ID)
+				pipe.Set(ctx, r.hashKey(key.Hash), key.ID, 0)
+				if key.PreviousHash != "" && key.PreviousValidUntil != nil {
+					if grace := time.Until(*key.PreviousValidUntil); grace > 0 {
+						pipe.Set(ctx, r.hashKey(key.PreviousHash), key.ID, grace)
+					}
+				}
+				return nil
+			})
+			saved = key
+			return err
+		}, r.idKey(id))
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	switch {
+	case err != nil && err == rejected:
+		return nil, err // The caller's own verdict, e.g. a missing or revoked key
+	case err != nil:
+		return nil, fmt.Errorf("failed to save API key: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *RedisAPIKeyStore) idKey(id string) string {
+	return r.prefix + ":" + id
+}
+
+func (r *RedisAPIKeyStore) hashKey(hash string) string {
+	return r.prefix + ":hash:" + hash
+}
+
+func decodeAPIKey(raw []byte) (*entity.APIKey, error) {
+	var stored storedAPIKey
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, fmt.Errorf("corrupt API key record: %w", err)
+	}
+	key := stored.APIKey
+	key.Hash, key.PreviousHash = stored.Hash, stored.PreviousHash
+	return &key, nil
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sentinel-core/internal/domain/entity"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const auditScanPageSize = 500
+
+// RedisAuditSink appends audit events to a Redis stream, one entry per event with the
+// JSON under "event". The chain is linear, so each gateway instance needs its own stream.
+type RedisAuditSink struct {
+	client *redis.Client
+	stream string
+}
+
+func NewRedisAuditSink(client *redis.Client, stream string) *RedisAuditSink {
+	return &RedisAuditSink{client: client, stream: stream}
+}
+
+func (r *RedisAuditSink) Append(ctx context.Context, event entity.AuditEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := r.client.XAdd(ctx, &redis.XAddArgs{Stream: r.stream, Values: map[string]any{"event": raw}}).Err(); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisAuditSink) Last(ctx context.Context) (*entity.AuditEvent, error) {
+	msgs, err := r.client.XRevRangeN(ctx, r.stream, "+", "-", 1).Result()
+	if err != nil || len(msgs) == 0 {
+		return nil, err
+	}
+	return decodeAuditMessage(msgs[0])
+}
+
+func (r *RedisAuditSink) Scan(ctx context.Context, fn func(entity.AuditEvent) error) error {
+	start := "-"
+	for {
+		msgs, err := r.client.XRangeN(ctx, r.stream, start, "+", auditScanPageSize).Result()
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			event, err := decodeAuditMessage(msg)
+			if err != nil {
+				return err
+			}
+			if err := fn(*event); err != nil {
+				return err
+			}
+		}
+		if len(msgs) < auditScanPageSize {
+			return nil
+		}
+		start = "(" + msgs[len(msgs)-1].ID // Exclusive, so the last entry is not read twice
+	}
+}
+
+// --- Private Helpers ---
+
+func decodeAuditMessage(msg redis.XMessage) (*entity.AuditEvent, error) {
+	raw, _ := msg.Values["event"].(string)
+	var event entity.AuditEvent
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		return nil, fmt.Errorf("corrupt audit entry %s: %w", msg.ID, err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sentinel-core/internal/domain/entity"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisDataKeyStore keeps the wrapped data keys of a tenant in the hash
+// tenant:<id>:datakeys, one field per version.
+type RedisDataKeyStore struct {
+	client *redis.Client
+}
+
+func NewRedisDataKeyStore(client *redis.Client) *RedisDataKeyStore {
+	return &RedisDataKeyStore{client: client}
+}
+
+func (r *RedisDataKeyStore) Latest(ctx context.Context, tenantID string) (*entity.DataKey, error) {
+	fields, err := r.client.HGetAll(ctx, dataKeysKey(tenantID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	var latest *entity.DataKey
+	for _, raw := range fields {
+		key, err := decodeDataKey(raw)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil || key.Version > latest.Version {
+			latest = key
+		}
+	}
+	if latest == nil {
+		return nil, entity.ErrResourceNotFound
+	}
+	return latest, nil
+}
+
+func (r *RedisDataKeyStore) Get(ctx context.Context, tenantID string, version int) (*entity.DataKey, error) {
+	raw, err := r.client.HGet(ctx, dataKeysKey(tenantID), strconv.Itoa(version)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, entity.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeDataKey(raw)
+}
+
+func (r *RedisDataKeyStore) Create(ctx context.Context, key entity.DataKey) (bool, error) {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return false, err
+	}
+	return r.client.HSetNX(ctx, dataKeysKey(key.TenantID), strconv.Itoa(key.Version), raw).Result()
+}
+
+func (r *RedisDataKeyStore) Save(ctx context.Context, key entity.DataKey) error {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return r.client.HSet(ctx, dataKeysKey(key.TenantID), strconv.Itoa(key.Version), raw).Err()
+}
+
+// --- Private Helpers ---
+
+func dataKeysKey(tenantID string) string {
+	return "tenant:" + tenantID + ":datakeys"
+}
+
+func decodeDataKey(raw string) (*entity.DataKey, error) {
+	var key entity.DataKey
+	if err := json.Unmarshal([]byte(raw), &key); err != nil {
+		return nil, fmt.Errorf("corrupt data key record: %w", err)
+	}
+	return &key, nil
+}
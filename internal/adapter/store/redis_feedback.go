@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sentinel-core/internal/domain/entity"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	feedbackStream = "feedback:events"
+
+	// Votes are kept well past the life of the entries they rate; the TTL only bounds
+	// the keys of entries long gone
+	feedbackVotePrefix = "feedback:vote:"
+	feedbackVoteTTL    = 90 * 24 * time.Hour
+)
+
+// RedisFeedbackStore appends feedback events to a Redis stream so they can be
+// consumed later for analysis (e.g. exported to Clickhouse).
+type RedisFeedbackStore struct {
+	client *redis.Client
+	maxLen int64 // Approximate cap on the stream length
+}
+
+func NewRedisFeedbackStore(client *redis.Client, maxLen int64) *RedisFeedbackStore {
+	return &RedisFeedbackStore{
+		client: client,
+		maxLen: maxLen,
+	}
+}
+
+func (r *RedisFeedbackStore) Record(ctx context.Context, fb entity.Feedback) error {
+	return r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: feedbackStream,
+		MaxLen: r.maxLen,
+		Approx: true,
+		Values: map[string]any{
+			"response_id": fb.ResponseID,
+			"user_id":     fb.UserID,
+			"rating":      string(fb.Rating),
+			"comment":     fb.Comment,
+			"timestamp":   fb.Timestamp.Format(time.RFC3339),
+		},
+	}).Err()
+}
+
+// SwapVote keeps one vote per response and user, swapped atomically so concurrent
+// re-votes cannot both count as first votes.
+func (r *RedisFeedbackStore) SwapVote(ctx context.Context, fb entity.Feedback) (entity.Rating, error) {
+	key := feedbackVotePrefix + fb.ResponseID + ":" + fb.UserID
+	var previous string
+	var err error
+	if fb.Rating == "" {
+		previous, err = r.client.GetDel(ctx, key).Result()
+	} else {
+		previous, err = r.client.SetArgs(ctx, key, string(fb.Rating), redis.SetArgs{Get: true, TTL: feedbackVoteTTL}).Result()
+	}
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return entity.Rating(previous), err
+}
@@ -2,14 +2,18 @@ package store
 
 import (
 	"context"
+	"sentinel-core/internal/domain/entity"
 	"strconv"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// RedisLimiter counts token usage per user under usage:<user> (default tenant) or
+// tenant:<id>:usage:<user>, and per tenant under tenant:<id>:usage.
 type RedisLimiter struct {
 	client *redis.Client
-	limit  int // Max tokens allowed
+	limit  int            // Max tokens allowed
+	tiers  map[string]int // Max tokens per rate tier, replacing limit
 }
 
 func NewRedisLimiter(client *redis.Client, limit int) *RedisLimiter {
@@ -19,15 +23,62 @@ func NewRedisLimiter(client *redis.Client, limit int) *RedisLimiter {
 	}
 }
 
-func (r *RedisLimiter) CheckLimit(ctx context.Context, userID string) (bool, error) {
-	val, err := r.client.Get(ctx, "usage:"+userID).Result()
+// WithTiers sets the token budgets of named rate tiers (e.g. from API keys).
+func (r *RedisLimiter) WithTiers(tiers map[string]int) *RedisLimiter {
+	r.tiers = tiers
+	return r
+}
+
+func (r *RedisLimiter) CheckLimit(ctx context.Context, scope entity.UsageScope) (bool, error) {
+	limit := r.limit
+	if scope.UserBudget > 0 {
+		limit = scope.UserBudget
+	}
+	if n, ok := r.tiers[scope.RateTier]; ok {
+		limit = n
+	}
+	if r.usage(ctx, userUsageKey(scope)) >= limit {
+		return false, nil
+	}
+	if scope.TenantBudget > 0 && r.usage(ctx, tenantUsageKey(scope.TenantID)) >= scope.TenantBudget {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (r *RedisLimiter) Increment(ctx context.Context, scope entity.UsageScope, tokens int) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.IncrBy(ctx, userUsageKey(scope), int64(tokens))
+		// Counted for the default tenant too, a token_budget may be set on it at any time
+		pipe.IncrBy(ctx, tenantUsageKey(scope.TenantID), int64(tokens))
+		return nil
+	})
+	return err
+}
+
+// --- Private Helpers ---
+
+func (r *RedisLimiter) usage(ctx context.Context, key string) int {
+	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return true, nil // No usage yet
+		return 0 // No usage yet
 	}
 	usage, _ := strconv.Atoi(val)
-	return usage < r.limit, nil
+	return usage
+}
+
+// userUsageKey keeps the pre-tenancy key for the default tenant so existing usage carries over
+func userUsageKey(scope entity.UsageScope) string {
+	if isDefaultTenant(scope.TenantID) {
+		return "usage:" + scope.UserID
+	}
+	return "tenant:" + scope.TenantID + ":usage:" + scope.UserID
+}
+
+func tenantUsageKey(tenantID string) string {
+	return "tenant:" + tenantID + ":usage"
 }
 
-func (r *RedisLimiter) Increment(ctx context.Context, userID string, tokens int) error {
-	return r.client.IncrBy(ctx, "usage:"+userID, int64(tokens)).Err()
+func isDefaultTenant(id string) bool {
+	return id == "" || id == entity.DefaultTenant
 }
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sentinel-core/internal/domain/entity"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisTenantStore keeps each tenant as a JSON document under tenant:<id>:config, next to
+// the tenant:<id>:usage counters of RedisLimiter, with the IDs in the tenants set.
+type RedisTenantStore struct {
+	client *redis.Client
+}
+
+func NewRedisTenantStore(client *redis.Client) *RedisTenantStore {
+	return &RedisTenantStore{client: client}
+}
+
+func (r *RedisTenantStore) Save(ctx context.Context, tenant entity.Tenant) error {
+	raw, err := json.Marshal(tenant)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, tenantConfigKey(tenant.ID), raw, 0)
+		pipe.SAdd(ctx, "tenants", tenant.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save tenant: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisTenantStore) Get(ctx context.Context, id string) (*entity.Tenant, error) {
+	raw, err := r.client.Get(ctx, tenantConfigKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, entity.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeTenant(raw)
+}
+
+func (r *RedisTenantStore) List(ctx context.Context) ([]entity.Tenant, error) {
+	ids, err := r.client.SMembers(ctx, "tenants").Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = tenantConfigKey(id)
+	}
+	docs, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []entity.Tenant
+	for _, doc := range docs {
+		raw, ok := doc.(string)
+		if !ok {
+			continue
+		}
+		tenant, err := decodeTenant([]byte(raw))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *tenant)
+	}
+	slices.SortFunc(out, func(a, b entity.Tenant) int { return strings.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
+// Delete removes the configuration; the tenant's usage counters are kept.
+func (r *RedisTenantStore) Delete(ctx context.Context, id string) error {
+	var removed *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		removed = pipe.Del(ctx, tenantConfigKey(id))
+		pipe.SRem(ctx, "tenants", id)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	if removed.Val() == 0 {
+		return entity.ErrResourceNotFound
+	}
+	return nil
+}
+
+// --- Private Helpers ---
+
+func tenantConfigKey(id string) string {
+	return "tenant:" + id + ":config"
+}
+
+func decodeTenant(raw []byte) (*entity.Tenant, error) {
+	var tenant entity.Tenant
+	if err := json.Unmarshal(raw, &tenant); err != nil {
+		return nil, fmt.Errorf("corrupt tenant record: %w", err)
+	}
+	return &tenant, nil
+}
@@ -0,0 +1,470 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sentinel-core/internal/domain/entity"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Separator for the tags field; values containing it are not filterable
+const redisTagSeparator = "|"
+
+// Hash fields returned by searches (everything except the raw vectors)
+var redisEntryFields = []string{"prompt", "content", "created_at", "payload", "feedback_up", "feedback_down", "feedback_score", entity.HitCountKey, entity.LastHitAtKey}
+
+// hitScript bumps the hit bookkeeping of an existing entry, or returns nil for a missing key
+var hitScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then return false end
+redis.call('HINCRBY', KEYS[1], 'hit_count', 1)
+redis.call('HSET', KEYS[1], 'last_hit_at', ARGV[1])
+return 1
+`)
+
+// feedbackScript increments the tally atomically and returns {up, down}, or nil for a missing key
+var feedbackScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then return false end
+local up = redis.call('HINCRBY', KEYS[1], 'feedback_up', ARGV[1])
+local down = redis.call('HINCRBY', KEYS[1], 'feedback_down', ARGV[2])
+redis.call('HSET', KEYS[1], 'feedback_score', up - down)
+return {up, down}
+`)
+
+// RedisVectorStore is a repository.VectorStore on Redis vector search (Redis Stack / RediSearch).
+// Entries are hashes under "<index>:<id>" carrying the prompt, content, created_at, the JSON
+// payload and a "key=value" tag field that makes every payload field filterable, mirroring
+// QdrantStore semantics. Keys expire natively once older than any lookup could accept.
+//
+// The client must use RESP2 (Protocol: 2); go-redis does not parse search replies over RESP3.
+type RedisVectorStore struct {
+	client       *redis.Client
+	index        string
+	ttl          time.Duration // Key expiry; 0 keeps entries until deleted
+	searchVector string        // Vector field searched by default when document vectors are stored
+}
+
+func NewRedisVectorStore(client *redis.Client, index string, ttl time.Duration) *RedisVectorStore {
+	return &RedisVectorStore{client: client, index: index, ttl: ttl, searchVector: entity.QueryVectorName}
+}
+
+// SearchDocumentVectors makes lookups compare against stored document vectors by default
+// (the dual embedding layout with EMBEDDING_SEARCH_VECTOR=document).
+func (s *RedisVectorStore) SearchDocumentVectors() {
+	s.searchVector = entity.DocumentVectorName
+}
+
+// InitIndex creates the search index (algorithm HNSW or FLAT) if it is missing and
+// validates an existing one against the embedder's dimension.
+func (s *RedisVectorStore) InitIndex(ctx context.Context, spec CollectionSpec, algorithm string) error {
+	info, err := s.client.FTInfo(ctx, s.index).Result()
+	if err == nil {
+		for _, attr := range info.Attributes {
+			if attr.Identifier == "embedding" && attr.Dim != int(spec.Dimension) {
+				return fmt.Errorf("%w: index %s has dimension %d, embedder produces %d (drop the index or re-import the cache)",
+					ErrCollectionMismatch, s.index, attr.Dim, spec.Dimension)
+			}
+		}
+		return nil
+	}
+	if !strings.Contains(strings.ToLower(err.Error()), "unknown index") && !strings.Contains(strings.ToLower(err.Error()), "no such index") {
+		return fmt.Errorf("failed to inspect index %s: %w", s.index, err)
+	}
+
+	vectorField := func(name string) *redis.FieldSchema {
+		args := &redis.FTVectorArgs{}
+		if strings.EqualFold(algorithm, "FLAT") {
+			args.FlatOptions = &redis.FTFlatOptions{Type: "FLOAT32", Dim: int(spec.Dimension), DistanceMetric: "COSINE"}
+		} else {
+			args.HNSWOptions = &redis.FTHNSWOptions{Type: "FLOAT32", Dim: int(spec.Dimension), DistanceMetric: "COSINE"}
+		}
+		return &redis.FieldSchema{FieldName: name, FieldType: redis.SearchFieldTypeVector, VectorArgs: args}
+	}
+
+	err = s.client.FTCreate(ctx, s.index,
+		&redis.FTCreateOptions{OnHash: true, Prefix: []any{s.index + ":"}},
+		&redis.FieldSchema{FieldName: "prompt", FieldType: redis.SearchFieldTypeText},
+		&redis.FieldSchema{FieldName: "tags", FieldType: redis.SearchFieldTypeTag, Separator: redisTagSeparator, CaseSensitive: true},
+		&redis.FieldSchema{FieldName: "created_at", FieldType: redis.SearchFieldTypeNumeric, Sortable: true},
+		&redis.FieldSchema{FieldName: "feedback_score", FieldType: redis.SearchFieldTypeNumeric},
+		vectorField("embedding"),
+		vectorField("document_embedding"),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to create index %s: %w", s.index, err)
+	}
+	log.Printf("[REDIS-VECTOR] Created index %s (%s, dim %d)", s.index, strings.ToUpper(algorithm), spec.Dimension)
+	return nil
+}
+
+func (s *RedisVectorStore) Search(ctx context.Context, query entity.SearchQuery) ([]entity.CacheHit, error) {
+	var conds []string
+
+	// 1. Metadata Filters (User ID, intent, provenance)
+	for key, value := range query.Filters {
+		conds = append(conds, filterCondition(key, value))
+	}
+
+	// 2. Freshness Filter (The TTL); key expiry drops anything older than the retention
+	if query.MaxAge > 0 {
+		conds = append(conds, fmt.Sprintf("@created_at:[%d +inf]", time.Now().Add(-query.MaxAge).Unix()))
+	}
+
+	// 3. Exclude entries users have rated down more than up
+	conds = append(conds, "-@feedback_score:[-inf (0]")
+
+	limit := max(query.Limit, 1)
+	knn := fmt.Sprintf("(%s)=>[KNN %d @%s $vec AS dist]", strings.Join(conds, " "), limit, s.vectorField(query.Using))
+	res, err := s.client.FTSearchWithArgs(ctx, s.index, knn, &redis.FTSearchOptions{
+		Return:         returnFields(append(redisEntryFields, "dist")...),
+		SortBy:         []redis.FTSearchSortBy{{FieldName: "dist", Asc: true}},
+		Limit:          limit,
+		Params:         map[string]any{"vec": vectorBytes(query.Vector)},
+		DialectVersion: 2,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Score threshold: cosine distance is 1 - similarity, results arrive nearest first
+	hits := make([]entity.CacheHit, 0, len(res.Docs))
+	for _, doc := range res.Docs {
+		dist, _ := strconv.ParseFloat(doc.Fields["dist"], 64)
+		score := float32(1 - dist)
+		if score < query.Threshold {
+			break
+		}
+		entry := s.toCacheEntry(doc.ID, doc.Fields)
+		hits = append(hits, entity.CacheHit{
+			Response: &entity.AIResponse{
+				ID:      entry.ID,
+				Content: entry.Content,
+				Cached:  true,
+			},
+			Score:     score,
+			Prompt:    entry.Prompt,
+			CreatedAt: entry.CreatedAt,
+			Payload:   entry.Metadata,
+		})
+	}
+	return hits, nil
+}
+
+func (s *RedisVectorStore) Save(ctx context.Context, record entity.CacheRecord) error {
+	return s.SaveBatch(ctx, []entity.CacheRecord{record})
+}
+
+// SaveBatch writes every record in one MULTI/EXEC pipeline
+func (s *RedisVectorStore) SaveBatch(ctx context.Context, records []entity.CacheRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, record := range records {
+			if err := s.queueSave(ctx, pipe, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisVectorStore) Get(ctx context.Context, id string) (*entity.CacheEntry, error) {
+	values, err := s.client.HMGet(ctx, s.key(id), redisEntryFields...).Result()
+	if err != nil {
+		return nil, err
+	}
+	if values[0] == nil {
+		return nil, entity.ErrResourceNotFound
+	}
+
+	fields := make(map[string]string, len(values))
+	for i, v := range values {
+		if str, ok := v.(string); ok {
+			fields[redisEntryFields[i]] = str
+		}
+	}
+	entry := s.toCacheEntry(s.key(id), fields)
+	return &entry, nil
+}
+
+// List pages through entries newest first; the cursor is the offset of the next page.
+func (s *RedisVectorStore) List(ctx context.Context, filter entity.CacheFilter, limit int, cursor string) (*entity.CachePage, error) {
+	offset := 0
+	if cursor != "" {
+		n, err := strconv.Atoi(cursor)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: invalid cursor", entity.ErrInvalidRequest)
+		}
+		offset = n
+	}
+
+	res, err := s.client.FTSearchWithArgs(ctx, s.index, adminQuery(filter), &redis.FTSearchOptions{
+		Return:         returnFields(redisEntryFields...),
+		SortBy:         []redis.FTSearchSortBy{{FieldName: "created_at", Desc: true}},
+		LimitOffset:    offset,
+		Limit:          limit,
+		DialectVersion: 2,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.CachePage{Entries: make([]entity.CacheEntry, 0, len(res.Docs))}
+	for _, doc := range res.Docs {
+		page.Entries = append(page.Entries, s.toCacheEntry(doc.ID, doc.Fields))
+	}
+	if next := offset + len(res.Docs); next < res.Total {
+		page.NextCursor = strconv.Itoa(next)
+	}
+	return page, nil
+}
+
+func (s *RedisVectorStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = s.key(id)
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+func (s *RedisVectorStore) DeleteByFilter(ctx context.Context, filter entity.CacheFilter) error {
+	if filter.IsEmpty() {
+		return fmt.Errorf("%w: refusing to delete with an empty filter", entity.ErrInvalidRequest)
+	}
+
+	// Deleted hashes leave the index immediately, so keep taking the first batch
+	query := adminQuery(filter)
+	for {
+		res, err := s.client.FTSearchWithArgs(ctx, s.index, query, &redis.FTSearchOptions{
+			NoContent:      true,
+			Limit:          500,
+			DialectVersion: 2,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		if len(res.Docs) == 0 {
+			return nil
+		}
+		keys := make([]string, len(res.Docs))
+		for i, doc := range res.Docs {
+			keys[i] = doc.ID
+		}
+		if err := s.client.Del(ctx, keys...).Err(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *RedisVectorStore) RecordHit(ctx context.Context, id string) error {
+	err := hitScript.Run(ctx, s.client, []string{s.key(id)}, time.Now().Unix()).Err()
+	if errors.Is(err, redis.Nil) {
+		return entity.ErrResourceNotFound
+	}
+	return err
+}
+
+// ApplyFeedback increments the tally in a script, so concurrent votes are not lost.
+func (s *RedisVectorStore) ApplyFeedback(ctx context.Context, id string, vote entity.Vote) (entity.FeedbackTally, error) {
+	up, down := vote.Delta()
+
+	counts, err := feedbackScript.Run(ctx, s.client, []string{s.key(id)}, up, down).Int64Slice()
+	if errors.Is(err, redis.Nil) {
+		return entity.FeedbackTally{}, entity.ErrResourceNotFound
+	}
+	if err != nil {
+		return entity.FeedbackTally{}, err
+	}
+	return entity.FeedbackTally{Up: counts[0], Down: counts[1]}, nil
+}
+
+// --- Private Helpers ---
+
+func (s *RedisVectorStore) queueSave(ctx context.Context, pipe redis.Pipeliner, record entity.CacheRecord) error {
+	payload, err := json.Marshal(record.Metadata)
+	if err != nil {
+		return err
+	}
+
+	// Reuse the response ID so feedback on the response lands on this entry
+	id := record.Response.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	fields := map[string]any{
+		"prompt":         record.Prompt,
+		"content":        record.Response.Content,
+		"created_at":     time.Now().Unix(),
+		"payload":        payload,
+		"tags":           payloadTags(record.Metadata),
+		"feedback_score": 0,
+		"embedding":      vectorBytes(record.Vector),
+	}
+	if record.DocumentVector != nil {
+		fields["document_embedding"] = vectorBytes(record.DocumentVector)
+	}
+
+	// Replace the whole entry like a Qdrant upsert, so a refresh also resets feedback
+	key := s.key(id)
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, fields)
+	if s.ttl > 0 {
+		pipe.Expire(ctx, key, s.ttl)
+	}
+	return nil
+}
+
+func (s *RedisVectorStore) key(id string) string {
+	return s.index + ":" + id
+}
+
+// vectorField picks the field to search; document vectors only exist in the dual layout
+func (s *RedisVectorStore) vectorField(requested string) string {
+	if requested == "" {
+		requested = s.searchVector
+	}
+	if requested == entity.DocumentVectorName {
+		return "document_embedding"
+	}
+	return "embedding"
+}
+
+func (s *RedisVectorStore) toCacheEntry(key string, fields map[string]string) entity.CacheEntry {
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	entry := entity.CacheEntry{
+		ID:        strings.TrimPrefix(key, s.index+":"),
+		Prompt:    fields["prompt"],
+		Content:   fields["content"],
+		CreatedAt: time.Unix(createdAt, 0),
+		Metadata:  make(map[string]any),
+	}
+	if raw := fields["payload"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &entry.Metadata); err != nil || entry.Metadata == nil {
+			entry.Metadata = make(map[string]any)
+		}
+	}
+	// Feedback and hits live in their own hash fields so scripts can increment them
+	if fields["feedback_up"] != "" || fields["feedback_down"] != "" {
+		for _, k := range []string{"feedback_up", "feedback_down", "feedback_score"} {
+			n, _ := strconv.ParseInt(fields[k], 10, 64)
+			entry.Metadata[k] = n
+		}
+	}
+	for _, k := range []string{entity.HitCountKey, entity.LastHitAtKey} {
+		if n, err := strconv.ParseInt(fields[k], 10, 64); err == nil {
+			entry.Metadata[k] = n
+		}
+	}
+	return entry
+}
+
+// adminQuery translates an admin filter; Exclude behaves like Qdrant's must_not,
+// so entries without the field are kept.
+func adminQuery(filter entity.CacheFilter) string {
+	var conds []string
+	if filter.UserID != "" {
+		conds = append(conds, tagCondition("user_id", filter.UserID))
+	}
+	for key, value := range filter.Metadata {
+		conds = append(conds, filterCondition(key, value))
+	}
+	for key, value := range filter.Exclude {
+		conds = append(conds, "-"+tagCondition(key, value))
+	}
+	if words := strings.Fields(filter.Text); len(words) > 0 {
+		for i, w := range words {
+			words[i] = escapeQuery(w)
+		}
+		conds = append(conds, "@prompt:("+strings.Join(words, " ")+")")
+	}
+	if len(conds) == 0 {
+		return "*"
+	}
+	return strings.Join(conds, " ")
+}
+
+// filterCondition also matches entries without the tag where they were cached before
+// the field was recorded
+func filterCondition(key, value string) string {
+	if entity.MatchesUnrecorded(key, value) {
+		return "(" + tagCondition(key, value) + " | -@tags:{" + escapeQuery(key+"=") + "*})"
+	}
+	return tagCondition(key, value)
+}
+
+func tagCondition(key, value string) string {
+	return "@tags:{" + escapeQuery(key+"="+value) + "}"
+}
+
+// payloadTags flattens string payload fields (and string lists) into "key=value" tags,
+// the Redis counterpart of Qdrant's keyword match on any payload field.
+func payloadTags(payload map[string]any) string {
+	var tags []string
+	add := func(k, v string) {
+		if !strings.Contains(v, redisTagSeparator) {
+			tags = append(tags, k+"="+v)
+		}
+	}
+	for k, v := range payload {
+		switch val := v.(type) {
+		case string:
+			add(k, val)
+		case []string:
+			for _, item := range val {
+				add(k, item)
+			}
+		case []any:
+			for _, item := range val {
+				if str, ok := item.(string); ok {
+					add(k, str)
+				}
+			}
+		}
+	}
+	return strings.Join(tags, redisTagSeparator)
+}
+
+// escapeQuery backslash-escapes everything but letters and digits for the query syntax
+func escapeQuery(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func returnFields(names ...string) []redis.FTSearchReturn {
+	fields := make([]redis.FTSearchReturn, len(names))
+	for i, name := range names {
+		fields[i] = redis.FTSearchReturn{FieldName: name}
+	}
+	return fields
+}
+
+// vectorBytes encodes a vector as little-endian FLOAT32, the layout Redis expects
+func vectorBytes(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"path"
+	"slices"
+	"time"
+)
+
+// APIKeyPrefix starts every issued API key, so leaked keys are easy to scan for
+const APIKeyPrefix = "sk_sentinel_"
+
+// APIKey is a stored API key. Only the SHA-256 of the secret is kept; the secret itself
+// is shown once, when the key is created or rotated.
+type APIKey struct {
+	ID            string     `json:"id"`
+	Hint          string     `json:"hint"` // First characters of the secret, to tell keys apart
+	Hash          string     `json:"-"`
+	Owner         string     `json:"owner"` // Becomes the user_id of every request made with the key
+	TenantID      string     `json:"tenant_id,omitempty"`
+	AllowedModels []string   `json:"allowed_models,omitempty"` // Glob patterns; empty allows every model
+	RateTier      string     `json:"rate_tier,omitempty"`      // Token budget tier; empty is the default limit
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+
+	// The secret replaced by the last rotation stays valid until PreviousValidUntil
+	PreviousHash       string     `json:"-"`
+	PreviousValidUntil *time.Time `json:"previous_valid_until,omitempty"`
+}
+
+// APIKeySpec is what an admin sets when creating a key.
+type APIKeySpec struct {
+	Owner         string     `json:"owner"`
+	TenantID      string     `json:"tenant_id"`
+	AllowedModels []string   `json:"allowed_models"`
+	RateTier      string     `json:"rate_tier"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+// IssuedAPIKey is returned once on creation and rotation: the only time the secret is visible.
+type IssuedAPIKey struct {
+	APIKey
+	Secret string `json:"secret"`
+}
+
+// Usable reports whether the key may authenticate at t.
+func (k APIKey) Usable(t time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || t.Before(*k.ExpiresAt))
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID        string
+	TenantID      string
+	AllowedModels []string
+	RateTier      string
+	Roles         []string // From the identity provider's token
+	KeyID         string   // API key the request was made with, empty for tokens
+}
+
+// AllowsModel reports whether the caller may use model (glob patterns, empty allows all).
+func AllowsModel(allowed []string, model string) bool {
+	return len(allowed) == 0 || slices.ContainsFunc(allowed, func(p string) bool {
+		ok, _ := path.Match(p, model)
+		return ok
+	})
+}
@@ -0,0 +1,72 @@
+package entity
+
+import "time"
+
+// AuditCacheDecision says how the cache took part in answering a request.
+type AuditCacheDecision string
+
+const (
+	AuditCacheHit       AuditCacheDecision = "hit"
+	AuditCacheStale     AuditCacheDecision = "stale"     // Hit past its freshness window, refreshed in the background
+	AuditCacheMiss      AuditCacheDecision = "miss"      // Generated by a model
+	AuditCacheCoalesced AuditCacheDecision = "coalesced" // Shared an identical in-flight generation
+	AuditCacheBypass    AuditCacheDecision = "bypass"    // The caller skipped the lookup (no-cache)
+	AuditCacheNone      AuditCacheDecision = "none"      // The request failed before the cache
+)
+
+// AuditEvent records one gateway request: who asked what of which model and what came
+// back. Prompt and answer are kept as SHA-256 hashes unless raw text auditing is on.
+//
+// Events form a hash chain: Hash covers the event (Hash itself excluded) and PrevHash, the
+// hash of the event before it, so editing, removing or reordering events breaks the chain.
+type AuditEvent struct {
+	Seq      int64     `json:"seq"`
+	Time     time.Time `json:"time"`
+	PrevHash string    `json:"prev_hash"`
+	Hash     string    `json:"hash"`
+
+	// Identity
+	UserID   string `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+	KeyID    string `json:"key_id,omitempty"` // API key used, empty for tokens or unauthenticated calls
+	Route    string `json:"route,omitempty"`
+
+	// What was asked and answered
+	ResponseID   string             `json:"response_id,omitempty"`
+	Model        string             `json:"model,omitempty"`
+	Provider     string             `json:"provider,omitempty"`
+	Cache        AuditCacheDecision `json:"cache"`
+	CachePolicy  string             `json:"cache_policy,omitempty"`
+	PromptHash   string             `json:"prompt_hash"`
+	ResponseHash string             `json:"response_hash,omitempty"`
+	Prompt       string             `json:"prompt,omitempty"`   // Only with raw text auditing
+	Response     string             `json:"response,omitempty"` // Only with raw text auditing
+	TokenCount   int                `json:"token_count"`
+	Cost         float64            `json:"cost"`
+	LatencyMs    int64              `json:"latency_ms"`
+
+	// Policy decisions
+	Redacted         map[PIIType]int   `json:"redacted,omitempty"` // PII found per type
+	RulesMatched     []string          `json:"rules_matched,omitempty"`
+	PolicyViolations []PolicyViolation `json:"policy_violations,omitempty"`
+	InjectionRisk    float64           `json:"injection_risk,omitempty"`
+
+	Outcome string `json:"outcome"`         // "ok", or the error class that stopped the request
+	Error   string `json:"error,omitempty"` // Error message of a failed request
+	Lost    int64  `json:"lost,omitempty"`  // Gap records only: events the sink failed to take
+}
+
+// AuditOutcomeGap marks a record standing in for events that could not be written, so the
+// loss is part of the chain instead of silently missing from it.
+const AuditOutcomeGap = "audit_gap"
+
+// AuditVerification is the result of checking an audit chain.
+type AuditVerification struct {
+	Events   int64   `json:"events"`
+	Valid    bool    `json:"valid"`
+	BrokenAt int64   `json:"broken_at,omitempty"` // Seq of the first event that does not verify
+	Reason   string  `json:"reason,omitempty"`
+	Head     string  `json:"head,omitempty"` // Hash of the last verified event
+	Gaps     []int64 `json:"gaps,omitempty"` // Seq of each gap record
+	Lost     int64   `json:"lost,omitempty"` // Events lost across all gaps
+}
@@ -0,0 +1,112 @@
+package entity
+
+import "time"
+
+// CacheEntry is a single answer stored in the semantic cache, as seen by the admin API.
+type CacheEntry struct {
+	ID        string         `json:"id"`
+	Prompt    string         `json:"prompt"`
+	Content   string         `json:"content"`
+	Metadata  map[string]any `json:"metadata"` // user_id, extracted intent, etc.
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// CacheFilter narrows admin operations to a subset of the cache.
+// Empty fields are ignored; an entirely empty filter matches everything.
+type CacheFilter struct {
+	UserID   string            `json:"user_id"`
+	Metadata map[string]string `json:"metadata"` // e.g. {"action": "transfer"}
+	Exclude  map[string]string `json:"exclude"`  // entries whose field equals the value are skipped
+	Text     string            `json:"text"`     // full-text match on the cached prompt
+
+	// Fields narrows List to these payload fields when set, so bookkeeping scans skip
+	// loading (and decrypting) prompts and answers. Stores may return more.
+	Fields []string `json:"-"`
+}
+
+// IsEmpty reports whether the filter would match the whole cache.
+func (f CacheFilter) IsEmpty() bool {
+	return f.UserID == "" && len(f.Metadata) == 0 && len(f.Exclude) == 0 && f.Text == ""
+}
+
+// CachePage is one page of a cache listing. NextCursor is empty on the last page.
+type CachePage struct {
+	Entries    []CacheEntry `json:"entries"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// SearchQuery describes a semantic cache lookup.
+type SearchQuery struct {
+	Vector    []float32
+	Threshold float32           // Minimum similarity score
+	Filters   map[string]string // Exact-match payload filters (the cache scope)
+	MaxAge    time.Duration     // Ignore entries older than this; zero means no limit
+	Limit     int               // Number of candidates to return; zero means 1
+	Using     string            // Named vector to search (dual layout); empty uses the store default
+}
+
+// CacheHit is one candidate returned by a semantic cache lookup.
+type CacheHit struct {
+	Response  *AIResponse
+	Score     float32
+	Prompt    string // The prompt the cached answer was generated for
+	CreatedAt time.Time
+	Payload   map[string]any // Caller metadata stored with the entry
+}
+
+// Age reports how old the cached answer is.
+func (h *CacheHit) Age() time.Duration {
+	return time.Since(h.CreatedAt)
+}
+
+// TTLKey is the payload key of an entry's own cache lifetime in seconds, set by policy rules
+const TTLKey = "ttl_seconds"
+
+// Expired reports whether the entry outlived its own TTL (entries without one never do).
+func (h *CacheHit) Expired() bool {
+	ttl := payloadInt(h.Payload, TTLKey)
+	return ttl > 0 && h.Age() > time.Duration(ttl)*time.Second
+}
+
+// CacheRecord is everything persisted for one generated answer.
+type CacheRecord struct {
+	Prompt         string
+	Response       *AIResponse
+	Vector         []float32      // Vector compared against lookups (single layout) / the query vector (dual layout)
+	DocumentVector []float32      // Document-task embedding, required by the dual layout
+	Sparse         *SparseVector  // Lexical (BM25-style) representation, optional
+	Metadata       map[string]any // Scope, intent and lexical signature fields
+}
+
+// SparseVector is a bag-of-words representation using hashed token indices.
+type SparseVector struct {
+	Indices []uint32
+	Values  []float32
+}
+
+// CacheExport is one line of a cache import/export file (JSONL).
+type CacheExport struct {
+	ID             string         `json:"id,omitempty"` // Kept on import when set, so re-imports overwrite
+	Prompt         string         `json:"prompt"`
+	Content        string         `json:"content"`
+	Metadata       map[string]any `json:"metadata,omitempty"` // user_id, intent and provenance fields
+	Vector         []float32      `json:"vector,omitempty"`
+	EmbeddingModel string         `json:"embedding_model,omitempty"` // Model that produced Vector
+}
+
+// ImportTarget is who imported entries are cached for. An empty TenantID means the default
+// tenant; an empty UserID shares the entries tenant-wide, which is only served to tenants
+// whose cache_scope is "tenant".
+type ImportTarget struct {
+	TenantID string
+	UserID   string
+}
+
+// ImportSummary reports the progress of a cache import.
+type ImportSummary struct {
+	Read       int      `json:"read"`
+	Imported   int      `json:"imported"`
+	Reembedded int      `json:"reembedded"`
+	Failed     int      `json:"failed"`
+	Errors     []string `json:"errors,omitempty"` // First few failures, by line
+}
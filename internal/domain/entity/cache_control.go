@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CacheControl carries per-request semantic cache directives, mirroring the
+// HTTP Cache-Control request header.
+type CacheControl struct {
+	NoCache bool `json:"no_cache"` // Skip the cache lookup, always generate
+	NoStore bool `json:"no_store"` // Do not save the generated answer
+	MaxAge  *int `json:"max_age"`  // Oldest acceptable cached answer, in seconds
+}
+
+// SkipLookup reports whether the cache must not be consulted.
+// max-age=0 means no cached answer is young enough, same as no-cache.
+func (c CacheControl) SkipLookup() bool {
+	return c.NoCache || (c.MaxAge != nil && *c.MaxAge <= 0)
+}
+
+// MaxAgeDuration returns the caller's age limit, or zero when none was given.
+func (c CacheControl) MaxAgeDuration() time.Duration {
+	if c.MaxAge == nil || *c.MaxAge <= 0 {
+		return 0
+	}
+	return time.Duration(*c.MaxAge) * time.Second
+}
+
+// String renders the applied policy in Cache-Control syntax ("default" when unset).
+func (c CacheControl) String() string {
+	var parts []string
+	if c.NoCache {
+		parts = append(parts, "no-cache")
+	}
+	if c.NoStore {
+		parts = append(parts, "no-store")
+	}
+	if c.MaxAge != nil {
+		parts = append(parts, fmt.Sprintf("max-age=%d", *c.MaxAge))
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "strings"
+
+// EmbeddingTask tells the embedding model what the vector will be used for.
+type EmbeddingTask string
+
+const (
+	TaskRetrievalQuery     EmbeddingTask = "RETRIEVAL_QUERY"
+	TaskRetrievalDocument  EmbeddingTask = "RETRIEVAL_DOCUMENT"
+	TaskSemanticSimilarity EmbeddingTask = "SEMANTIC_SIMILARITY"
+)
+
+// EmbeddingMode decides which vectors the semantic cache stores and searches with.
+type EmbeddingMode string
+
+const (
+	EmbedModeQuery      EmbeddingMode = "query"      // Query vectors on both sides (legacy behaviour)
+	EmbedModeDocument   EmbeddingMode = "document"   // Store document vectors, search with query vectors
+	EmbedModeSimilarity EmbeddingMode = "similarity" // Semantic-similarity vectors on both sides
+	EmbedModeDual       EmbeddingMode = "dual"       // Store query and document vectors side by side
+)
+
+// Named vectors used by the dual layout.
+const (
+	QueryVectorName    = "query"
+	DocumentVectorName = "document"
+)
+
+// ParseEmbeddingMode falls back to the legacy query mode on unknown input.
+func ParseEmbeddingMode(s string) EmbeddingMode {
+	switch mode := EmbeddingMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case EmbedModeDocument, EmbedModeSimilarity, EmbedModeDual:
+		return mode
+	default:
+		return EmbedModeQuery
+	}
+}
+
+// LookupTask is the task type used to embed incoming prompts.
+func (m EmbeddingMode) LookupTask() EmbeddingTask {
+	if m == EmbedModeSimilarity {
+		return TaskSemanticSimilarity
+	}
+	return TaskRetrievalQuery
+}
+
+// NeedsDocumentVector reports whether saving requires a separate document embedding.
+func (m EmbeddingMode) NeedsDocumentVector() bool {
+	return m == EmbedModeDocument || m == EmbedModeDual
+}
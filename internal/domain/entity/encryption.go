@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// DataKey is a tenant's data encryption key as stored: wrapped by a master key, so
+// the store never holds it in the clear. Rotation adds a version; older versions stay
+// readable until the entries sealed with them are re-encrypted.
+type DataKey struct {
+	TenantID    string    `json:"tenant_id"`
+	Version     int       `json:"version"`
+	MasterKeyID string    `json:"master_key_id"` // Master key Wrapped is encrypted under
+	Wrapped     []byte    `json:"wrapped"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReencryptionSummary reports one pass over the encrypted cache.
+type ReencryptionSummary struct {
+	Scanned     int `json:"scanned"`
+	Reencrypted int `json:"reencrypted"` // Sealed with a retired key, or still in plaintext
+	Failed      int `json:"failed"`      // Could not be decrypted (e.g. a missing master key)
+}
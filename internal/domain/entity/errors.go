@@ -8,4 +8,11 @@ var (
 	ErrInternalServer    = errors.New("an internal error occurred")
 	ErrInvalidRequest    = errors.New("invalid request parameters")
 	ErrResourceNotFound  = errors.New("the requested resource was not found")
+	ErrPromptInjection   = errors.New("prompt rejected: possible prompt injection")
+	ErrPolicyViolation   = errors.New("response withheld: output policy violation")
+	ErrRequestDenied     = errors.New("request denied by policy")
+	ErrApprovalRequired  = errors.New("request requires approval")
+	ErrUnauthorized      = errors.New("missing or invalid credentials")
+	ErrModelNotAllowed   = errors.New("model not allowed for these credentials")
+	ErrUnknownTenant     = errors.New("unknown tenant")
 )
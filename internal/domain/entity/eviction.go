@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Payload keys tracking how often and how recently an entry was served
+const (
+	HitCountKey  = "hit_count"
+	LastHitAtKey = "last_hit_at" // Unix seconds
+)
+
+// EvictionPolicy decides which entries go first when a scope is over its quota.
+type EvictionPolicy string
+
+const (
+	EvictLeastRecentlyHit EvictionPolicy = "lru"    // Oldest last hit (or creation) first
+	EvictLeastHit         EvictionPolicy = "lfu"    // Lowest hit count first, oldest among equals
+	EvictOldest           EvictionPolicy = "oldest" // Oldest creation first
+)
+
+// ParseEvictionPolicy reads a policy name, defaulting to least-recently-hit.
+func ParseEvictionPolicy(s string) EvictionPolicy {
+	switch p := EvictionPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case EvictLeastHit, EvictOldest:
+		return p
+	default:
+		return EvictLeastRecentlyHit
+	}
+}
+
+// CacheQuota bounds the number of cached entries per user and per tenant.
+type CacheQuota struct {
+	Default   int            // Entries per user; zero means unbounded
+	Overrides map[string]int // Per-user limits replacing the default, keyed "<tenant>/<user>" ("<user>" in the default tenant)
+
+	TenantDefault   int            // Entries per tenant, shared entries included; zero means unbounded
+	TenantOverrides map[string]int // Per-tenant limits replacing TenantDefault
+}
+
+// Limit returns the quota of a user within a tenant; zero means unbounded.
+func (q CacheQuota) Limit(tenant, user string) int {
+	if n, ok := q.Overrides[tenant+"/"+user]; ok {
+		return n
+	}
+	if n, ok := q.Overrides[user]; ok && tenant == DefaultTenant {
+		return n
+	}
+	return q.Default
+}
+
+// TenantLimit returns the quota of a whole tenant; zero means unbounded.
+func (q CacheQuota) TenantLimit(tenant string) int {
+	if n, ok := q.TenantOverrides[tenant]; ok {
+		return n
+	}
+	return q.TenantDefault
+}
+
+// IsEmpty reports whether no scope is bounded.
+func (q CacheQuota) IsEmpty() bool {
+	if q.Default > 0 || q.TenantDefault > 0 {
+		return false
+	}
+	for _, overrides := range []map[string]int{q.Overrides, q.TenantOverrides} {
+		for _, n := range overrides {
+			if n > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ParseQuotaOverrides reads "acme/alice=1000,bob=50"; malformed pairs are skipped.
+func ParseQuotaOverrides(s string) map[string]int {
+	overrides := make(map[string]int)
+	for pair := range strings.SplitSeq(s, ",") {
+		scope, limit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || scope == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(limit); err == nil && n >= 0 {
+			overrides[scope] = n
+		}
+	}
+	return overrides
+}
+
+// HitStats reads the hit bookkeeping of a cache entry.
+func (e CacheEntry) HitStats() (hits int64, lastHit time.Time) {
+	hits = payloadInt(e.Metadata, HitCountKey)
+	if ts := payloadInt(e.Metadata, LastHitAtKey); ts > 0 {
+		return hits, time.Unix(ts, 0)
+	}
+	return hits, e.CreatedAt
+}
+
+// payloadInt reads a number stored by any backend (JSON floats, integers or strings)
+func payloadInt(payload map[string]any, key string) int64 {
+	switch v := payload[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+type Rating string
+
+const (
+	RatingUp   Rating = "up"
+	RatingDown Rating = "down"
+)
+
+// Feedback is a user's verdict on a single AIResponse.
+type Feedback struct {
+	ResponseID string    `json:"response_id"`
+	UserID     string    `json:"user_id"`
+	TenantID   string    `json:"tenant_id,omitempty"`
+	Rating     Rating    `json:"rating"`
+	Comment    string    `json:"comment,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// FeedbackTally is the running count stored alongside a cache entry.
+type FeedbackTally struct {
+	Up   int64 `json:"up"`
+	Down int64 `json:"down"`
+}
+
+// Vote is one change to an entry's tally: a user's rating, replacing the one they gave
+// before so repeated votes never count twice.
+type Vote struct {
+	Rating   Rating
+	Previous Rating // Empty for the user's first vote on the entry
+}
+
+// Delta is what the vote adds to the up and down counts; re-casting the same rating adds nothing.
+func (v Vote) Delta() (up, down int64) {
+	up, down = ratingCounts(v.Rating)
+	prevUp, prevDown := ratingCounts(v.Previous)
+	return up - prevUp, down - prevDown
+}
+
+// Score is the net rating; entries below zero are no longer served from the cache.
+func (t FeedbackTally) Score() int64 {
+	return t.Up - t.Down
+}
+
+func ratingCounts(r Rating) (up, down int64) {
+	switch r {
+	case RatingUp:
+		return 1, 0
+	case RatingDown:
+		return 0, 1
+	}
+	return 0, 0
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "strings"
+
+// Payload fields recording what produced a cached answer.
+const (
+	ProviderKey        = "provider"
+	ModelKey           = "model"
+	ModelFamilyKey     = "model_family"
+	OptionsHashKey     = "options_hash"
+	TemplateVersionKey = "template_version"
+)
+
+// CompatibilityPolicy decides which cached answers may be served for the current model.
+type CompatibilityPolicy string
+
+const (
+	CompatSameModel  CompatibilityPolicy = "model"  // Only answers from the exact same model
+	CompatSameFamily CompatibilityPolicy = "family" // Any model of the same family, e.g. gemini-2.5-*
+	CompatAny        CompatibilityPolicy = "any"    // Whatever model produced it
+)
+
+// ParseCompatibilityPolicy falls back to the strictest policy on unknown input.
+func ParseCompatibilityPolicy(s string) CompatibilityPolicy {
+	switch CompatibilityPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case CompatSameFamily:
+		return CompatSameFamily
+	case CompatAny:
+		return CompatAny
+	default:
+		return CompatSameModel
+	}
+}
+
+// MatchesUnrecorded reports a lookup filter that should also match entries lacking the
+// field: those cached before multi-tenancy belong to the default tenant, and those cached
+// before provenance was recorded are taken as compatible with any model, template and options.
+func MatchesUnrecorded(key, value string) bool {
+	switch key {
+	case TenantIDKey:
+		return value == DefaultTenant
+	case ModelKey, ModelFamilyKey, OptionsHashKey, TemplateVersionKey:
+		return true
+	}
+	return false
+}
+
+// GenerationProfile identifies the model and prompt template answering requests.
+type GenerationProfile struct {
+	Provider        string // e.g. "gemini"
+	Model           string // e.g. "gemini-2.5-flash"
+	TemplateVersion string // Bumped whenever system instructions change
+}
+
+// ModelFamily groups models by vendor and generation: "gemini-2.5-flash-lite" -> "gemini-2.5".
+func ModelFamily(model string) string {
+	parts := strings.SplitN(model, "-", 3)
+	if len(parts) < 2 {
+		return model
+	}
+	return parts[0] + "-" + parts[1]
+}
@@ -0,0 +1,24 @@
+package entity
+
+// InjectionScore is one classifier's view of how likely a prompt is an injection
+// or jailbreak attempt.
+type InjectionScore struct {
+	Risk    float64  // 0 (benign) to 1 (certain attack)
+	Signals []string // Short reasons, e.g. "instruction_override"
+}
+
+// InjectionAction is what the guard decided for a prompt.
+type InjectionAction string
+
+const (
+	InjectionAllow InjectionAction = "allow"
+	InjectionFlag  InjectionAction = "flag"  // Served, but not cached and marked in metadata
+	InjectionBlock InjectionAction = "block" // Rejected with ErrPromptInjection
+)
+
+// InjectionAssessment combines every classifier's score for a prompt.
+type InjectionAssessment struct {
+	Risk    float64         `json:"risk"`
+	Signals []string        `json:"signals,omitempty"`
+	Action  InjectionAction `json:"action"`
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+)
+
+// LexicalSignature captures the exact tokens cosine similarity tends to blur:
+// "transfer 500 from A to B" and "transfer 50 from B to A" embed almost identically.
+type LexicalSignature struct {
+	Numbers   []string // Normalized numbers, amounts, dates, account numbers (sorted, with repeats)
+	Entities  []string // Named entities and quoted terms, lowercased (sorted, unique)
+	Relations []string // Directional roles, e.g. "from:savings", "to:checking" (sorted, unique)
+	Terms     []string // Every lowercased word, used to match entities regardless of casing (sorted, unique)
+}
+
+// Payload field names used to persist a signature with a cache entry.
+const (
+	LexNumbersKey   = "lex_numbers"
+	LexEntitiesKey  = "lex_entities"
+	LexRelationsKey = "lex_relations"
+	LexTermsKey     = "lex_terms"
+)
+
+// Agrees reports whether two prompts mention the same critical tokens: identical
+// numbers and directional roles, and every entity of either prompt present in the other.
+func (s LexicalSignature) Agrees(other LexicalSignature) bool {
+	return slices.Equal(s.Numbers, other.Numbers) &&
+		slices.Equal(s.Relations, other.Relations) &&
+		other.mentionsAll(s.Entities) &&
+		s.mentionsAll(other.Entities)
+}
+
+func (s LexicalSignature) mentionsAll(entities []string) bool {
+	for _, e := range entities {
+		for _, word := range strings.Fields(e) {
+			if _, found := slices.BinarySearch(s.Terms, word); !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Payload renders the signature as payload fields for the vector store.
+func (s LexicalSignature) Payload() map[string]any {
+	return map[string]any{
+		LexNumbersKey:   toAnySlice(s.Numbers),
+		LexEntitiesKey:  toAnySlice(s.Entities),
+		LexRelationsKey: toAnySlice(s.Relations),
+		LexTermsKey:     toAnySlice(s.Terms),
+	}
+}
+
+// LexicalSignatureFromPayload restores a signature stored by Payload.
+// The bool is false when the entry predates lexical signatures.
+func LexicalSignatureFromPayload(payload map[string]any) (LexicalSignature, bool) {
+	if _, ok := payload[LexNumbersKey]; !ok {
+		return LexicalSignature{}, false
+	}
+	return LexicalSignature{
+		Numbers:   fromAnySlice(payload[LexNumbersKey]),
+		Entities:  fromAnySlice(payload[LexEntitiesKey]),
+		Relations: fromAnySlice(payload[LexRelationsKey]),
+		Terms:     fromAnySlice(payload[LexTermsKey]),
+	}, true
+}
+
+func toAnySlice(values []string) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+func fromAnySlice(v any) []string {
+	items, _ := v.([]any)
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
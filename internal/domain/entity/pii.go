@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PIIType classifies a detected piece of personal data.
+type PIIType string
+
+const (
+	PIIEmail      PIIType = "EMAIL"
+	PIIPhone      PIIType = "PHONE"
+	PIICard       PIIType = "CARD"
+	PIIIBAN       PIIType = "IBAN"
+	PIINationalID PIIType = "NATIONAL_ID"
+	PIICustom     PIIType = "CUSTOM" // Terms from a custom dictionary
+)
+
+// PIIMatch is one detection, as a byte range of the scanned text.
+type PIIMatch struct {
+	Type  PIIType
+	Start int
+	End   int
+}
+
+// Redaction is a prompt with its PII replaced, plus what was found.
+type Redaction struct {
+	Text   string
+	Counts map[PIIType]int   // Detections per type; empty when nothing was found
+	Tokens map[string]string // Reversible mode only: token -> original value, for this request only
+}
+
+// Found reports whether anything was redacted.
+func (r Redaction) Found() bool {
+	return len(r.Counts) > 0
+}
+
+// Rehydrate puts the original values back in place of this request's tokens.
+// Tokens the text does not contain, or unknown ones, are left alone.
+func (r Redaction) Rehydrate(text string) string {
+	if len(r.Tokens) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(r.Tokens))
+	for token, value := range r.Tokens {
+		pairs = append(pairs, token, value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Placeholder is the stable masking placeholder for the n-th distinct value of a type, e.g. [EMAIL_1].
+func Placeholder(t PIIType, n int) string {
+	return fmt.Sprintf("[%s_%d]", t, n)
+}
+
+// Token is the reversible token for the n-th distinct value of a type, e.g. <EMAIL_1>.
+func Token(t PIIType, n int) string {
+	return fmt.Sprintf("<%s_%d>", t, n)
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PolicyAction is what an output rule does to a response that violates it.
+type PolicyAction string
+
+const (
+	PolicyBlock    PolicyAction = "block"    // Fail the request with a *PolicyError
+	PolicyRedact   PolicyAction = "redact"   // Fix the response (mask, truncate, append) and serve it
+	PolicyAnnotate PolicyAction = "annotate" // Serve the response as is, reporting the violation
+)
+
+// OutputRuleKind selects what an output rule checks.
+type OutputRuleKind string
+
+const (
+	RuleDenyPattern OutputRuleKind = "deny_pattern" // Any of Patterns matches
+	RulePIILeakage  OutputRuleKind = "pii"          // A PII detector matches (optionally only PIITypes)
+	RuleMaxLength   OutputRuleKind = "max_length"   // Longer than MaxLength characters
+	RuleDisclaimer  OutputRuleKind = "disclaimer"   // Disclaimer missing for one of Intents
+)
+
+// OutputRule is one configurable post-generation check, as read from the policy file.
+type OutputRule struct {
+	Name   string         `json:"name"`
+	Kind   OutputRuleKind `json:"kind"`
+	Action PolicyAction   `json:"action"`
+
+	Patterns   []string  `json:"patterns,omitempty"`   // deny_pattern: Go regular expressions
+	PIITypes   []PIIType `json:"pii_types,omitempty"`  // pii: empty means every type
+	MaxLength  int       `json:"max_length,omitempty"` // max_length: in characters
+	Intents    []string  `json:"intents,omitempty"`    // disclaimer: extracted actions it applies to
+	Disclaimer string    `json:"disclaimer,omitempty"` // disclaimer: text required in (or appended to) the answer
+}
+
+// OutputPolicyConfig is the policy file layout.
+type OutputPolicyConfig struct {
+	Rules []OutputRule `json:"rules"`
+}
+
+// PolicyViolation is one rule a response broke and what was done about it.
+type PolicyViolation struct {
+	Rule   string       `json:"rule"`
+	Kind   string       `json:"kind"`
+	Action PolicyAction `json:"action"`
+	Detail string       `json:"detail,omitempty"`
+}
+
+// PolicyError is returned when a blocking output rule rejects a generated response.
+// It matches ErrPolicyViolation with errors.Is.
+type PolicyError struct {
+	Violations []PolicyViolation
+}
+
+func (e *PolicyError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if v.Action == PolicyBlock {
+			rules = append(rules, v.Rule)
+		}
+	}
+	return fmt.Sprintf("%v: %s", ErrPolicyViolation, strings.Join(rules, ", "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicyViolation
+}
@@ -4,6 +4,7 @@ import "time"
 
 type AIRequest struct {
 	UserID   string `json:"user_id"`
+	TenantID string `json:"tenant_id"`
 	Prompt   string `json:"prompt"`
 	Provider string `json:"provider"` // e.g., "gemini", "claude"
 	Model    string `json:"model"`    // e.g., "gemini-2.5-flash"
@@ -15,15 +16,30 @@ type AIRequest struct {
 	// Optional: Allow the user to tweak the "creativity" per request
 	Temperature float32   `json:"temperature"`
 	Timestamp   time.Time `json:"timestamp"`
+
+	// Optional: Per-request cache directives (also read from the Cache-Control header)
+	Cache CacheControl `json:"cache"`
+
+	// Set by the gateway, never by the caller
+	Route         string       `json:"-"` // Route path the request came in on
+	Policy        RuleDecision `json:"-"` // What the policy rules decided for this request
+	RateTier      string       `json:"-"` // Token budget tier of the authenticated caller
+	AllowedModels []string     `json:"-"` // Models the caller's credentials allow; empty allows all
+	KeyID         string       `json:"-"` // API key the caller authenticated with
+	Tenant        Tenant       `json:"-"` // Configuration of the resolved TenantID
 }
 
 type AIResponse struct {
-	Content    string         `json:"content"`
-	Cached     bool           `json:"cached"` // Was this from Qdrant?
-	Score      float32        `json:"score"`  // Similarity score for debugging
-	Model      string         `json:"model"`  // Which model actually answered?
-	TokenCount int            `json:"token_count"`
-	Cost       float64        `json:"cost"`
-	Latency    int64          `json:"latency_ms"` // How fast was the response?
-	Metadata   map[string]any `json:"metadata"`
+	ID          string         `json:"id"` // Reference for feedback; equals the cache entry ID
+	Content     string         `json:"content"`
+	Cached      bool           `json:"cached"`       // Was this from Qdrant?
+	Stale       bool           `json:"stale"`        // Served past its freshness window while being refreshed
+	CachePolicy string         `json:"cache_policy"` // Cache directives applied to this request
+	Score       float32        `json:"score"`        // Similarity score for debugging
+	Model       string         `json:"model"`        // Which model actually answered?
+	Provider    string         `json:"provider"`     // Which vendor served that model?
+	TokenCount  int            `json:"token_count"`
+	Cost        float64        `json:"cost"`
+	Latency     int64          `json:"latency_ms"` // How fast was the response?
+	Metadata    map[string]any `json:"metadata"`
 }
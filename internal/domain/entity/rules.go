@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+// RequestRuleSet is the layout of the rules file (YAML or JSON).
+type RequestRuleSet struct {
+	Rules []RequestRule `json:"rules" yaml:"rules"`
+}
+
+// RequestRule applies its effects to every request matching all of its conditions.
+// Rules are evaluated in file order; a final rule stops the evaluation when it matches.
+type RequestRule struct {
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description,omitempty" yaml:"description"`
+	Match       RuleConditions `json:"match" yaml:"match"`
+	Then        RuleEffects    `json:"then" yaml:"then"`
+	Final       bool           `json:"final,omitempty" yaml:"final"`
+}
+
+// RuleConditions are ANDed together; a list matches when any of its entries does and
+// an empty condition matches everything. Users, tenants, routes and models take glob
+// patterns ("gemini-2.5-*"), metadata values are compared case-insensitively.
+type RuleConditions struct {
+	Users          []string            `json:"users,omitempty" yaml:"users"`
+	Tenants        []string            `json:"tenants,omitempty" yaml:"tenants"`
+	Routes         []string            `json:"routes,omitempty" yaml:"routes"`
+	Models         []string            `json:"models,omitempty" yaml:"models"`
+	Metadata       map[string][]string `json:"metadata,omitempty" yaml:"metadata"` // Extracted action/source/target
+	PromptPatterns []string            `json:"prompt_patterns,omitempty" yaml:"prompt_patterns"`
+}
+
+// RuleEffects is what a matching rule does to the request. force_redaction from a rule
+// without metadata conditions applies before the prompt leaves the gateway; a rule matching
+// extracted metadata only fires after the extractor (and a model injection classifier)
+// has seen the original prompt, so it cannot keep PII from those two calls.
+type RuleEffects struct {
+	Deny            bool   `json:"deny,omitempty" yaml:"deny"`
+	Reason          string `json:"reason,omitempty" yaml:"reason"` // Returned to the caller on deny
+	ForceModel      string `json:"force_model,omitempty" yaml:"force_model"`
+	DisableCache    bool   `json:"disable_cache,omitempty" yaml:"disable_cache"`
+	ForceRedaction  bool   `json:"force_redaction,omitempty" yaml:"force_redaction"`
+	TTL             string `json:"ttl,omitempty" yaml:"ttl"` // Go duration the generated answer stays cached
+	RequireApproval bool   `json:"require_approval,omitempty" yaml:"require_approval"`
+}
+
+// RuleInput is everything the rules can match on.
+type RuleInput struct {
+	UserID   string            `json:"user_id"`
+	TenantID string            `json:"tenant_id"`
+	Route    string            `json:"route"`
+	Model    string            `json:"model"`
+	Prompt   string            `json:"prompt"`
+	Metadata map[string]string `json:"metadata"` // nil before extraction: metadata conditions never match
+}
+
+// RuleDecision merges the effects of every matching rule. Flags are ORed, for the
+// other effects the first rule setting them wins.
+type RuleDecision struct {
+	Matched         []string `json:"matched"`
+	Deny            bool     `json:"deny"`
+	Reason          string   `json:"reason,omitempty"`
+	ForceModel      string   `json:"force_model,omitempty"`
+	DisableCache    bool     `json:"disable_cache"`
+	ForceRedaction  bool     `json:"force_redaction"`
+	TTLSeconds      int64    `json:"ttl_seconds,omitempty"`
+	RequireApproval bool     `json:"require_approval"`
+}
+
+// Stops reports whether the decision ends the request before any answer is produced.
+func (d RuleDecision) Stops() bool {
+	return d.Deny || d.RequireApproval
+}
+
+// TTL is how long the generated answer may be served from the cache; zero means the default.
+func (d RuleDecision) TTL() time.Duration {
+	return time.Duration(d.TTLSeconds) * time.Second
+}
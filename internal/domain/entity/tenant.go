@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// DefaultTenant owns requests that name no tenant
+const DefaultTenant = "default"
+
+// TenantIDKey is the payload key isolating cached answers per tenant
+const TenantIDKey = "tenant_id"
+
+// CacheScope decides who may be served a tenant's cached answers.
+type CacheScope string
+
+const (
+	CacheScopeUser   CacheScope = "user"   // Only the user whose request produced the answer (default)
+	CacheScopeTenant CacheScope = "tenant" // Every user of the tenant
+)
+
+// RedactionPolicy overrides the gateway's PII redaction for a tenant.
+type RedactionPolicy string
+
+const (
+	RedactionDefault  RedactionPolicy = ""         // Whatever the gateway is configured with
+	RedactionOff      RedactionPolicy = "off"      // No redaction (rules may still force it)
+	RedactionMask     RedactionPolicy = "mask"     // Irreversible placeholders
+	RedactionTokenize RedactionPolicy = "tokenize" // Reversible tokens, re-hydrated in the answer
+)
+
+// Tenant is an isolated customer of the gateway with its own limits and policies.
+// Zero values inherit the gateway defaults.
+type Tenant struct {
+	ID               string          `json:"id"`
+	Name             string          `json:"name,omitempty"`
+	AllowedProviders []string        `json:"allowed_providers,omitempty"` // Empty allows every provider
+	AllowedModels    []string        `json:"allowed_models,omitempty"`    // Glob patterns; empty allows every model
+	UserTokenBudget  int             `json:"user_token_budget,omitempty"` // Per-user budget, replacing USER_TOKEN_LIMIT
+	TokenBudget      int             `json:"token_budget,omitempty"`      // Shared by all users of the tenant; zero is unbounded
+	CacheScope       CacheScope      `json:"cache_scope,omitempty"`
+	CacheTTLSeconds  int64           `json:"cache_ttl_seconds,omitempty"` // Lifetime of the tenant's cached answers
+	Redaction        RedactionPolicy `json:"redaction,omitempty"`
+	FallbackChain    []string        `json:"fallback_chain,omitempty"` // Models tried in order, replacing the gateway's
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// CacheTTL is how long the tenant's answers stay cached; zero means the gateway freshness.
+func (t Tenant) CacheTTL() time.Duration {
+	return time.Duration(t.CacheTTLSeconds) * time.Second
+}
+
+// AllowsProvider reports whether the tenant may use a provider.
+func (t Tenant) AllowsProvider(provider string) bool {
+	return len(t.AllowedProviders) == 0 || slices.Contains(t.AllowedProviders, provider)
+}
+
+// UsageScope identifies whose token budgets a request draws from.
+type UsageScope struct {
+	TenantID     string
+	UserID       string
+	RateTier     string // Credential rate tier; its budget wins over UserBudget
+	UserBudget   int    // Tenant override of the per-user budget; zero uses the limiter default
+	TenantBudget int    // Cap on the whole tenant; zero is unbounded
+}
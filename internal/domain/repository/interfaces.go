@@ -6,13 +6,38 @@ import (
 )
 
 type VectorStore interface {
-	Search(ctx context.Context, vector []float32, threshold float32, filters map[string]string) (*entity.AIResponse, float32, string, error)
-	Save(ctx context.Context, prompt string, resp *entity.AIResponse, vector []float32, metadata map[string]any) error
+	// Search returns up to query.Limit candidates ordered by descending score
+	Search(ctx context.Context, query entity.SearchQuery) ([]entity.CacheHit, error)
+	Save(ctx context.Context, record entity.CacheRecord) error
+	// SaveBatch upserts many records in one round trip (imports, cache warming)
+	SaveBatch(ctx context.Context, records []entity.CacheRecord) error
+
+	// Admin operations
+	Get(ctx context.Context, id string) (*entity.CacheEntry, error)
+	List(ctx context.Context, filter entity.CacheFilter, limit int, cursor string) (*entity.CachePage, error)
+	Delete(ctx context.Context, ids ...string) error
+	DeleteByFilter(ctx context.Context, filter entity.CacheFilter) error
+
+	// RecordHit bumps the entry's hit_count and sets last_hit_at (eviction bookkeeping)
+	RecordHit(ctx context.Context, id string) error
+
+	// ApplyFeedback adds one vote to the entry's tally, net of the vote it replaces, and
+	// returns the new totals
+	ApplyFeedback(ctx context.Context, id string, vote entity.Vote) (entity.FeedbackTally, error)
+}
+
+type FeedbackRepository interface {
+	Record(ctx context.Context, fb entity.Feedback) error
+	// SwapVote stores a user's rating of a response (an empty rating clears it) and
+	// returns the rating it replaces, empty for a first vote
+	SwapVote(ctx context.Context, fb entity.Feedback) (entity.Rating, error)
 }
 
 type TokenLimiter interface {
-	CheckLimit(ctx context.Context, userID string) (bool, error)
-	Increment(ctx context.Context, userID string, tokens int) error
+	// CheckLimit compares usage with the user's budget (rate tier, tenant override or default)
+	// and with the tenant's shared budget
+	CheckLimit(ctx context.Context, scope entity.UsageScope) (bool, error)
+	Increment(ctx context.Context, scope entity.UsageScope, tokens int) error
 }
 
 type AIProvider interface {
@@ -20,13 +45,97 @@ type AIProvider interface {
 }
 
 type Embedder interface {
+	// CreateEmbedding embeds text as a retrieval query
 	CreateEmbedding(ctx context.Context, text string) ([]float32, error)
+	CreateEmbeddingFor(ctx context.Context, text string, task entity.EmbeddingTask) ([]float32, error)
 }
 
 type Evaluator interface {
-    IsMatch(ctx context.Context, userPrompt, cachedPrompt string) bool
+	IsMatch(ctx context.Context, userPrompt, cachedPrompt string) bool
 }
 
 type Extractor interface {
-    ExtractMetadata(ctx context.Context, prompt string) map[string]string
-}
\ No newline at end of file
+	ExtractMetadata(ctx context.Context, prompt string) map[string]string
+}
+
+// PIIDetector finds one kind of personal data in text
+type PIIDetector interface {
+	Detect(text string) []entity.PIIMatch
+}
+
+// InjectionClassifier scores a prompt for prompt-injection and jailbreak attempts
+type InjectionClassifier interface {
+	Classify(ctx context.Context, prompt string) (entity.InjectionScore, error)
+}
+
+// APIKeyStore persists API keys by the hash of their secret
+type APIKeyStore interface {
+	// Save upserts a key by ID, re-indexing it under its current (and previous) hash
+	Save(ctx context.Context, key entity.APIKey) error
+	Get(ctx context.Context, id string) (*entity.APIKey, error)
+	// Update applies fn to a stored key as one read-modify-write, so a rotation racing a
+	// revocation cannot write back what it read; ErrResourceNotFound when the key is missing
+	Update(ctx context.Context, id string, fn func(*entity.APIKey) error) (*entity.APIKey, error)
+	// FindByHash matches the current or previous hash; ErrResourceNotFound when none does
+	FindByHash(ctx context.Context, hash string) (*entity.APIKey, error)
+	// List returns the keys of an owner, or every key when owner is empty
+	List(ctx context.Context, owner string) ([]entity.APIKey, error)
+}
+
+// TokenVerifier authenticates bearer tokens issued by an identity provider
+type TokenVerifier interface {
+	// VerifyToken maps a valid token to its caller; invalid tokens are entity.ErrUnauthorized
+	VerifyToken(ctx context.Context, token string) (entity.Principal, error)
+}
+
+// TenantStore persists tenant configurations
+type TenantStore interface {
+	Save(ctx context.Context, tenant entity.Tenant) error
+	// Get returns ErrResourceNotFound for unknown tenants
+	Get(ctx context.Context, id string) (*entity.Tenant, error)
+	List(ctx context.Context) ([]entity.Tenant, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// AuditSink stores the audit chain. Events arrive in chain order from a single writer.
+type AuditSink interface {
+	Append(ctx context.Context, event entity.AuditEvent) error
+	// Last returns the newest event, nil when the sink is empty
+	Last(ctx context.Context) (*entity.AuditEvent, error)
+	// Scan visits every event in chain order until fn returns an error
+	Scan(ctx context.Context, fn func(entity.AuditEvent) error) error
+}
+
+// KeyManager wraps data keys under master keys it never releases (a KMS or a local key file)
+type KeyManager interface {
+	// WrapKey encrypts a data key under the current master key and names that key
+	WrapKey(ctx context.Context, dataKey []byte) (wrapped []byte, masterKeyID string, err error)
+	UnwrapKey(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error)
+	// CurrentKeyID is the master key new data keys are wrapped with
+	CurrentKeyID() string
+}
+
+// DataKeyStore persists the wrapped data keys of each tenant
+type DataKeyStore interface {
+	// Latest returns the newest version; ErrResourceNotFound when the tenant has none
+	Latest(ctx context.Context, tenantID string) (*entity.DataKey, error)
+	Get(ctx context.Context, tenantID string, version int) (*entity.DataKey, error)
+	// Create adds a new version; false when another writer created it first
+	Create(ctx context.Context, key entity.DataKey) (bool, error)
+	// Save overwrites an existing version (re-wrapping under a new master key)
+	Save(ctx context.Context, key entity.DataKey) error
+}
+
+// PayloadCipher encrypts cached payload fields with per-tenant keys
+type PayloadCipher interface {
+	// Seal encrypts a value with the tenant's current data key
+	Seal(ctx context.Context, tenantID string, plaintext []byte) (string, error)
+	// Open decrypts a sealed value; values that were never sealed are returned as-is.
+	// stale reports that the value should be sealed again (plaintext or a retired key).
+	Open(ctx context.Context, tenantID string, value string) (plaintext []byte, stale bool, err error)
+}
+
+// Reencrypter re-seals cache entries whose fields are stale
+type Reencrypter interface {
+	Reencrypt(ctx context.Context) (entity.ReencryptionSummary, error)
+}
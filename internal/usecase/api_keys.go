@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const apiKeyHintLength = len(entity.APIKeyPrefix) + 6
+
+// APIKeyService issues, rotates and revokes API keys and authenticates requests with them.
+type APIKeyService struct {
+	store repository.APIKeyStore
+	tiers []string // Rate tiers a key may be assigned
+}
+
+func NewAPIKeyService(store repository.APIKeyStore, tiers []string) *APIKeyService {
+	return &APIKeyService{store: store, tiers: tiers}
+}
+
+// Create issues a new key; the returned secret is never stored.
+func (s *APIKeyService) Create(ctx context.Context, spec entity.APIKeySpec) (entity.IssuedAPIKey, error) {
+	if strings.TrimSpace(spec.Owner) == "" {
+		return entity.IssuedAPIKey{}, fmt.Errorf("%w: owner is required", entity.ErrInvalidRequest)
+	}
+	if spec.RateTier != "" && !slices.Contains(s.tiers, spec.RateTier) {
+		return entity.IssuedAPIKey{}, fmt.Errorf("%w: unknown rate tier %q", entity.ErrInvalidRequest, spec.RateTier)
+	}
+	if spec.ExpiresAt != nil && !spec.ExpiresAt.After(time.Now()) {
+		return entity.IssuedAPIKey{}, fmt.Errorf("%w: expires_at is in the past", entity.ErrInvalidRequest)
+	}
+
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return entity.IssuedAPIKey{}, err
+	}
+	key := entity.APIKey{
+		ID:            uuid.NewString(),
+		Hint:          secret[:apiKeyHintLength],
+		Hash:          hashAPIKey(secret),
+		Owner:         spec.Owner,
+		TenantID:      spec.TenantID,
+		AllowedModels: spec.AllowedModels,
+		RateTier:      spec.RateTier,
+		CreatedAt:     time.Now().UTC(),
+		ExpiresAt:     spec.ExpiresAt,
+	}
+	if err := s.store.Save(ctx, key); err != nil {
+		return entity.IssuedAPIKey{}, err
+	}
+	log.Printf("[API-KEYS] Created key %s for %s", key.ID, key.Owner)
+	return entity.IssuedAPIKey{APIKey: key, Secret: secret}, nil
+}
+
+// Rotate replaces a key's secret. The old secret keeps working for the grace period
+// so clients can be switched over without downtime; zero cuts it off immediately.
+func (s *APIKeyService) Rotate(ctx context.Context, id string, grace time.Duration) (entity.IssuedAPIKey, error) {
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return entity.IssuedAPIKey{}, err
+	}
+
+	// The revocation check and the new secret are one store update, so a concurrent
+	// revocation either wins (and the rotation fails) or revokes the rotated key
+	key, err := s.store.Update(ctx, id, func(key *entity.APIKey) error {
+		if key.RevokedAt != nil {
+			return fmt.Errorf("%w: key %s is revoked", entity.ErrInvalidRequest, id)
+		}
+		key.PreviousHash, key.PreviousValidUntil = "", nil
+		if grace > 0 {
+			until := time.Now().Add(grace).UTC()
+			key.PreviousHash, key.PreviousValidUntil = key.Hash, &until
+		}
+		key.Hash = hashAPIKey(secret)
+		key.Hint = secret[:apiKeyHintLength]
+		return nil
+	})
+	if err != nil {
+		return entity.IssuedAPIKey{}, err
+	}
+	log.Printf("[API-KEYS] Rotated key %s (grace %s)", key.ID, grace)
+	return entity.IssuedAPIKey{APIKey: *key, Secret: secret}, nil
+}
+
+// Revoke disables a key for good; revoking twice is a no-op.
+func (s *APIKeyService) Revoke(ctx context.Context, id string) (*entity.APIKey, error) {
+	revoked := false
+	key, err := s.store.Update(ctx, id, func(key *entity.APIKey) error {
+		if key.RevokedAt == nil {
+			now := time.Now().UTC()
+			key.RevokedAt, revoked = &now, true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		log.Printf("[API-KEYS] Revoked key %s", key.ID)
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) Get(ctx context.Context, id string) (*entity.APIKey, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *APIKeyService) List(ctx context.Context, owner string) ([]entity.APIKey, error) {
+	return s.store.List(ctx, owner)
+}
+
+// Authenticate resolves a presented secret to the identity it was issued for.
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (entity.Principal, error) {
+	if !strings.HasPrefix(secret, entity.APIKeyPrefix) {
+		return entity.Principal{}, entity.ErrUnauthorized
+	}
+	hash := hashAPIKey(secret)
+	key, err := s.store.FindByHash(ctx, hash)
+	if errors.Is(err, entity.ErrResourceNotFound) {
+		return entity.Principal{}, entity.ErrUnauthorized
+	}
+	if err != nil {
+		return entity.Principal{}, err
+	}
+
+	now := time.Now()
+	if !key.Usable(now) {
+		return entity.Principal{}, entity.ErrUnauthorized
+	}
+	// The index only points at the key; the key itself decides which secrets are valid
+	current := subtle.ConstantTimeCompare([]byte(hash), []byte(key.Hash)) == 1
+	previous := key.PreviousHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(key.PreviousHash)) == 1 &&
+		key.PreviousValidUntil != nil && now.Before(*key.PreviousValidUntil)
+	if !current && !previous {
+		return entity.Principal{}, entity.ErrUnauthorized
+	}
+	return entity.Principal{
+		UserID:        key.Owner,
+		TenantID:      key.TenantID,
+		AllowedModels: key.AllowedModels,
+		RateTier:      key.RateTier,
+		KeyID:         key.ID,
+	}, nil
+}
+
+// --- Private Helpers ---
+
+// newAPIKeySecret draws 32 random bytes, enough that a fast hash is safe to store
+func newAPIKeySecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return entity.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
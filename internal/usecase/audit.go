@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"sync/atomic"
+	"time"
+)
+
+// Audit metrics, published on the admin /metrics endpoint
+var (
+	auditEvents   = expvar.NewInt("audit_events")
+	auditFailures = expvar.NewInt("audit_failures")
+)
+
+var (
+	// errStopScan ends a verification scan at the first broken link
+	errStopScan = errors.New("stop scan")
+	// errAuditBacklog is the gap reason for events dropped because the writer fell behind
+	errAuditBacklog = errors.New("audit buffer full, events dropped")
+)
+
+// Sink writes are retried this many times, backing off from auditRetryBackoff, before
+// the event is counted as lost and a gap record is owed
+const (
+	auditAttempts     = 5
+	auditRetryBackoff = 100 * time.Millisecond
+)
+
+// A request waits at most this long for room in a full buffer before its event is dropped
+// (and counted in the next gap record): a sink outage must not stall the gateway
+const auditEnqueueTimeout = 50 * time.Millisecond
+
+// auditOutcomes classifies the errors that stop a request, first match wins
+var auditOutcomes = []struct {
+	err     error
+	outcome string
+}{
+	{entity.ErrUnknownTenant, "unknown_tenant"},
+	{entity.ErrRateLimitExceeded, "rate_limited"},
+	{entity.ErrRequestDenied, "denied"},
+	{entity.ErrApprovalRequired, "approval_required"},
+	{entity.ErrModelNotAllowed, "model_not_allowed"},
+	{entity.ErrPromptInjection, "prompt_injection"},
+	{entity.ErrPolicyViolation, "policy_violation"},
+}
+
+// Auditor seals request events into a hash chain and writes them to a sink. A single
+// goroutine owns the chain head, so events are chained in the order they are recorded.
+type Auditor struct {
+	sink    repository.AuditSink
+	rawText bool // Keep prompts and answers verbatim next to their hashes
+	events  chan entity.AuditEvent
+	dropped atomic.Int64 // Events Record gave up on, folded into the next gap record
+
+	// Chain head and pending losses, only touched by run
+	seq     int64
+	head    string
+	lost    int64
+	lostErr error
+}
+
+// NewAuditor continues the chain already in the sink and starts the writer. buffer is how
+// many events may wait for the sink; events that find it full, like those the sink refuses,
+// are accounted for by a gap record rather than holding up requests.
+func NewAuditor(ctx context.Context, sink repository.AuditSink, rawText bool, buffer int) (*Auditor, error) {
+	last, err := sink.Last(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+	a := &Auditor{sink: sink, rawText: rawText, events: make(chan entity.AuditEvent, max(buffer, 1))}
+	if last != nil {
+		a.seq, a.head = last.Seq, last.Hash
+	}
+	go a.run(ctx)
+	log.Printf("[AUDIT] Chain continues after event %d", a.seq)
+	return a, nil
+}
+
+// Record queues an event for sealing; Seq, PrevHash and Hash are set by the writer.
+func (a *Auditor) Record(event entity.AuditEvent) {
+	select {
+	case a.events <- event:
+		return
+	default:
+	}
+	timer := time.NewTimer(auditEnqueueTimeout)
+	defer timer.Stop()
+	select {
+	case a.events <- event:
+	case <-timer.C:
+		a.dropped.Add(1)
+		auditFailures.Add(1)
+		log.Printf("[AUDIT] Buffer full, dropped event for %s/%s, a gap record will follow", event.TenantID, event.UserID)
+	}
+}
+
+// AuditHash is the SHA-256 of an event's JSON with Hash left out. PrevHash is part of it,
+// which links every event to the one before.
+func AuditHash(event entity.AuditEvent) string {
+	event.Hash = ""
+	raw, _ := json.Marshal(event)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditChain walks the sink from the first event and reports the first one whose
+// sequence, link or hash does not check out, and every gap record (events the sink lost).
+// Deleting events from the end leaves a valid shorter chain, so compare Head with a head
+// recorded elsewhere (the gateway logs it).
+func VerifyAuditChain(ctx context.Context, sink repository.AuditSink) (entity.AuditVerification, error) {
+	v := entity.AuditVerification{Valid: true}
+	err := sink.Scan(ctx, func(event entity.AuditEvent) error {
+		var reason string
+		switch {
+		case event.Seq != v.Events+1:
+			reason = fmt.Sprintf("expected seq %d", v.Events+1)
+		case event.PrevHash != v.Head:
+			reason = "prev_hash does not match the previous event (event removed or reordered)"
+		case AuditHash(event) != event.Hash:
+			reason = "hash does not match the content (event modified)"
+		}
+		if reason != "" {
+			v.Valid, v.BrokenAt, v.Reason = false, event.Seq, reason
+			return errStopScan
+		}
+		if event.Outcome == entity.AuditOutcomeGap {
+			v.Gaps = append(v.Gaps, event.Seq)
+			v.Lost += event.Lost
+		}
+		v.Events++
+		v.Head = event.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopScan) {
+		return v, err
+	}
+	return v, nil
+}
+
+// --- Private Helpers ---
+
+func (a *Auditor) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// An anchor outside the sink: truncating the chain cannot hide the logged heads
+			log.Printf("[AUDIT] Chain head %d %s", a.seq, a.head)
+		case event := <-a.events:
+			a.append(ctx, event)
+		}
+	}
+}
+
+// append seals and writes an event. Events the sink keeps refusing are not skipped
+// silently: the next successful write is preceded by a gap record counting them.
+func (a *Auditor) append(ctx context.Context, event entity.AuditEvent) {
+	if n := a.dropped.Swap(0); n > 0 {
+		a.lost += n
+		if a.lostErr == nil {
+			a.lostErr = errAuditBacklog
+		}
+	}
+	if a.lost > 0 {
+		gap := entity.AuditEvent{
+			Time:    time.Now().UTC(),
+			Outcome: entity.AuditOutcomeGap,
+			Error:   a.lostErr.Error(),
+			Lost:    a.lost,
+		}
+		if err := a.write(ctx, gap); err != nil {
+			a.lose(event, err)
+			return
+		}
+		log.Printf("[AUDIT] Recorded gap of %d lost events at seq %d", gap.Lost, a.seq)
+		a.lost, a.lostErr = 0, nil
+	}
+	if err := a.write(ctx, event); err != nil {
+		a.lose(event, err)
+	}
+}
+
+// write chains an event onto the head, retrying the sink with backoff
+func (a *Auditor) write(ctx context.Context, event entity.AuditEvent) error {
+	event.Seq = a.seq + 1
+	event.PrevHash = a.head
+	event.Hash = AuditHash(event)
+
+	backoff := auditRetryBackoff
+	var err error
+	for attempt := 1; attempt <= auditAttempts; attempt++ {
+		if err = a.sink.Append(ctx, event); err == nil {
+			a.seq, a.head = event.Seq, event.Hash
+			auditEvents.Add(1)
+			return nil
+		}
+		if attempt == auditAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+func (a *Auditor) lose(event entity.AuditEvent, err error) {
+	a.lost++
+	a.lostErr = err
+	auditFailures.Add(1)
+	log.Printf("[AUDIT] Failed to write event for %s/%s, a gap record will follow: %v", event.TenantID, event.UserID, err)
+}
+
+// audit completes the request's event from its final state and records it.
+func (u *Orchestrator) audit(event *entity.AuditEvent, req entity.AIRequest, prompt string, resp *entity.AIResponse, err error, started time.Time) {
+	if u.auditor == nil {
+		return
+	}
+	event.Time = started.UTC()
+	event.LatencyMs = time.Since(started).Milliseconds()
+	event.UserID, event.TenantID, event.KeyID, event.Route = req.UserID, req.TenantID, req.KeyID, req.Route
+	event.CachePolicy = req.Cache.String()
+	event.RulesMatched = req.Policy.Matched
+	event.PromptHash = hashText(prompt)
+	if u.auditor.rawText {
+		event.Prompt = prompt
+	}
+
+	event.Outcome = "ok"
+	if err != nil {
+		event.Outcome, event.Error = auditOutcome(err), err.Error()
+		var violation *entity.PolicyError
+		if errors.As(err, &violation) {
+			event.PolicyViolations = violation.Violations
+		}
+	}
+	if resp != nil {
+		event.ResponseID, event.Model, event.Provider = resp.ID, resp.Model, resp.Provider
+		event.TokenCount, event.Cost = resp.TokenCount, resp.Cost
+		event.ResponseHash = hashText(resp.Content)
+		if u.auditor.rawText {
+			event.Response = resp.Content
+		}
+		if violations, ok := resp.Metadata["policy_violations"].([]entity.PolicyViolation); ok {
+			event.PolicyViolations = violations
+		}
+	}
+	u.auditor.Record(*event)
+}
+
+func auditOutcome(err error) string {
+	for _, o := range auditOutcomes {
+		if errors.Is(err, o.err) {
+			return o.outcome
+		}
+	}
+	return "error"
+}
+
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// CacheAdmin exposes inspection and invalidation of the semantic cache.
+type CacheAdmin struct {
+	vectorStore repository.VectorStore
+}
+
+func NewCacheAdmin(vs repository.VectorStore) *CacheAdmin {
+	return &CacheAdmin{vectorStore: vs}
+}
+
+func (a *CacheAdmin) List(ctx context.Context, filter entity.CacheFilter, limit int, cursor string) (*entity.CachePage, error) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	return a.vectorStore.List(ctx, filter, limit, cursor)
+}
+
+func (a *CacheAdmin) Get(ctx context.Context, id string) (*entity.CacheEntry, error) {
+	if id == "" {
+		return nil, entity.ErrInvalidRequest
+	}
+	return a.vectorStore.Get(ctx, id)
+}
+
+func (a *CacheAdmin) Delete(ctx context.Context, id string) error {
+	// Look the entry up first so callers get a 404 instead of a silent no-op
+	if _, err := a.Get(ctx, id); err != nil {
+		return err
+	}
+	return a.vectorStore.Delete(ctx, id)
+}
+
+func (a *CacheAdmin) DeleteByFilter(ctx context.Context, filter entity.CacheFilter) error {
+	if filter.IsEmpty() {
+		return fmt.Errorf("%w: a filter is required", entity.ErrInvalidRequest)
+	}
+	return a.vectorStore.DeleteByFilter(ctx, filter)
+}
+
+// PurgeScope drops every entry a tenant cached for a user, optionally narrowed by intent
+// metadata. User IDs are only unique within a tenant, so both are required.
+func (a *CacheAdmin) PurgeScope(ctx context.Context, tenantID, userID string, meta map[string]string) error {
+	if tenantID == "" || userID == "" {
+		return fmt.Errorf("%w: tenant_id and user_id are required to purge a scope", entity.ErrInvalidRequest)
+	}
+	filter := entity.CacheFilter{UserID: userID, Metadata: maps.Clone(meta)}
+	if filter.Metadata == nil {
+		filter.Metadata = make(map[string]string, 1)
+	}
+	filter.Metadata[entity.TenantIDKey] = tenantID
+	return a.vectorStore.DeleteByFilter(ctx, filter)
+}
+
+// InvalidateTemplateVersions drops every entry not generated under the current
+// prompt-template version, including entries saved before versions were recorded.
+func (a *CacheAdmin) InvalidateTemplateVersions(ctx context.Context, current string) error {
+	if current == "" {
+		return fmt.Errorf("%w: template version is required", entity.ErrInvalidRequest)
+	}
+	return a.vectorStore.DeleteByFilter(ctx, entity.CacheFilter{
+		Exclude: map[string]string{entity.TemplateVersionKey: current},
+	})
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+const compactionPageSize = 500
+
+// compactionFields is all of an entry the compactor reads: prompts and answers are never loaded
+var compactionFields = []string{"user_id", entity.TenantIDKey, "created_at", entity.HitCountKey, entity.LastHitAtKey}
+
+// CacheCompactor enforces per-user and per-tenant quotas on the semantic cache, evicting
+// the surplus of every scope over its limit according to the eviction policy.
+type CacheCompactor struct {
+	vectorStore repository.VectorStore
+	quota       entity.CacheQuota
+	policy      entity.EvictionPolicy
+}
+
+func NewCacheCompactor(vs repository.VectorStore, quota entity.CacheQuota, policy entity.EvictionPolicy) *CacheCompactor {
+	return &CacheCompactor{vectorStore: vs, quota: quota, policy: policy}
+}
+
+// evictionCandidate is the little of an entry the policies need
+type evictionCandidate struct {
+	id        string
+	createdAt time.Time
+	hits      int64
+	lastHit   time.Time
+}
+
+// Compact scans the cache once and deletes the surplus of each scope over its quota: every
+// user is trimmed to their own limit first, then each tenant, shared entries included, to
+// the tenant limit. It returns how many entries were evicted.
+func (c *CacheCompactor) Compact(ctx context.Context) (int, error) {
+	if c.quota.IsEmpty() {
+		return 0, nil
+	}
+
+	// 1. Group the cache by tenant, then user ("" holds the tenant-wide entries)
+	tenants := make(map[string]map[string][]evictionCandidate)
+	cursor := ""
+	for {
+		page, err := c.vectorStore.List(ctx, entity.CacheFilter{Fields: compactionFields}, compactionPageSize, cursor)
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range page.Entries {
+			user, _ := entry.Metadata["user_id"].(string)
+			tenant, _ := entry.Metadata[entity.TenantIDKey].(string)
+			tenant = cmp.Or(tenant, entity.DefaultTenant)
+			if tenants[tenant] == nil {
+				tenants[tenant] = make(map[string][]evictionCandidate)
+			}
+			hits, lastHit := entry.HitStats()
+			tenants[tenant][user] = append(tenants[tenant][user], evictionCandidate{id: entry.ID, createdAt: entry.CreatedAt, hits: hits, lastHit: lastHit})
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	// 2. Pick victims per user, then per tenant among the survivors, first-to-go first
+	var victims []string
+	for tenant, users := range tenants {
+		var kept []evictionCandidate
+		for user, entries := range users {
+			slices.SortFunc(entries, c.evictionOrder)
+			surplus := 0
+			if limit := c.quota.Limit(tenant, user); user != "" && limit > 0 {
+				surplus = max(len(entries)-limit, 0)
+			}
+			if surplus > 0 {
+				log.Printf("[COMPACTION] Scope %s/%s over quota by %d (%s)", tenant, user, surplus, c.policy)
+			}
+			for _, e := range entries[:surplus] {
+				victims = append(victims, e.id)
+			}
+			kept = append(kept, entries[surplus:]...)
+		}
+
+		limit := c.quota.TenantLimit(tenant)
+		if limit <= 0 || len(kept) <= limit {
+			continue
+		}
+		surplus := len(kept) - limit
+		slices.SortFunc(kept, c.evictionOrder)
+		for _, e := range kept[:surplus] {
+			victims = append(victims, e.id)
+		}
+		log.Printf("[COMPACTION] Tenant %s over quota by %d (%s)", tenant, surplus, c.policy)
+	}
+
+	// 3. Delete in bounded batches
+	for batch := range slices.Chunk(victims, compactionPageSize) {
+		if err := c.vectorStore.Delete(ctx, batch...); err != nil {
+			return 0, err
+		}
+	}
+	return len(victims), nil
+}
+
+// Start runs Compact every interval until ctx is cancelled.
+func (c *CacheCompactor) Start(ctx context.Context, interval time.Duration) {
+	if c.quota.IsEmpty() || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				evicted, err := c.Compact(ctx)
+				if err != nil {
+					log.Printf("[COMPACTION] Failed: %v", err)
+				} else if evicted > 0 {
+					log.Printf("[COMPACTION] Evicted %d entries", evicted)
+				}
+			}
+		}
+	}()
+}
+
+// --- Private Helpers ---
+
+// evictionOrder sorts the entries to evict first to the front
+func (c *CacheCompactor) evictionOrder(a, b evictionCandidate) int {
+	var order int
+	switch c.policy {
+	case entity.EvictLeastHit:
+		order = cmp.Or(cmp.Compare(a.hits, b.hits), a.lastHit.Compare(b.lastHit))
+	case entity.EvictOldest:
+		order = a.createdAt.Compare(b.createdAt)
+	default:
+		order = a.lastHit.Compare(b.lastHit)
+	}
+	return cmp.Or(order, strings.Compare(a.id, b.id))
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultImportBatch = 64
+	maxImportErrors    = 20      // Failures reported back in the summary
+	maxImportLine      = 1 << 20 // Longest accepted JSONL line (1 MiB)
+	exportPageSize     = 500
+)
+
+// RecordBuilder turns an imported entry into a cache record; reuseVector says whether
+// the entry's own vector may be kept. It reports whether the prompt was re-embedded.
+type RecordBuilder func(ctx context.Context, item entity.CacheExport, reuseVector bool) (entity.CacheRecord, bool, error)
+
+// CacheTransfer imports curated answers into the semantic cache and exports its contents
+// as JSONL, for warming a new environment or moving the cache between environments.
+type CacheTransfer struct {
+	vectorStore    repository.VectorStore
+	build          RecordBuilder
+	embeddingModel string // Imported vectors from any other model are re-embedded
+}
+
+func NewCacheTransfer(vs repository.VectorStore, build RecordBuilder, embeddingModel string) *CacheTransfer {
+	return &CacheTransfer{vectorStore: vs, build: build, embeddingModel: embeddingModel}
+}
+
+// Import reads JSONL entries and upserts them in batches, calling progress after each batch
+// (nil logs it). Entries are scoped to target so lookups can serve them. Malformed lines are
+// counted and skipped; a failing batch write aborts the import.
+func (t *CacheTransfer) Import(ctx context.Context, r io.Reader, target entity.ImportTarget, batchSize int, progress func(entity.ImportSummary)) (entity.ImportSummary, error) {
+	if batchSize <= 0 {
+		batchSize = defaultImportBatch
+	}
+	if progress == nil {
+		progress = logImportProgress
+	}
+
+	var summary entity.ImportSummary
+	fail := func(line int, err error) {
+		summary.Failed++
+		if len(summary.Errors) < maxImportErrors {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("line %d: %v", line, err))
+		}
+	}
+
+	batch := make([]entity.CacheRecord, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := t.vectorStore.SaveBatch(ctx, batch); err != nil {
+			return fmt.Errorf("batch write failed after %d entries: %w", summary.Imported, err)
+		}
+		summary.Imported += len(batch)
+		batch = batch[:0]
+		progress(summary)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		summary.Read++
+
+		// 1. Parse and validate
+		var item entity.CacheExport
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			fail(line, err)
+			continue
+		}
+		if item.Prompt == "" || item.Content == "" {
+			fail(line, fmt.Errorf("prompt and content are required"))
+			continue
+		}
+		if item.ID != "" && uuid.Validate(item.ID) != nil {
+			fail(line, fmt.Errorf("id must be a UUID"))
+			continue
+		}
+
+		// 2. Vectors from another embedder are useless here
+		reuse := item.EmbeddingModel == "" || item.EmbeddingModel == t.embeddingModel
+		record, reembedded, err := t.build(ctx, item, reuse)
+		if err != nil {
+			fail(line, err)
+			continue
+		}
+		if reembedded {
+			summary.Reembedded++
+		}
+
+		// 3. Lookups always filter on the tenant, and on the user unless the tenant shares answers
+		lineTenant, _ := record.Metadata[entity.TenantIDKey].(string)
+		record.Metadata[entity.TenantIDKey] = cmp.Or(target.TenantID, lineTenant, entity.DefaultTenant)
+		if target.UserID != "" {
+			record.Metadata["user_id"] = target.UserID
+		}
+
+		// 4. Write full batches
+		batch = append(batch, record)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, fmt.Errorf("%w: %v", entity.ErrInvalidRequest, err)
+	}
+	return summary, flush()
+}
+
+// Export writes every entry matching the filter as JSONL and returns how many were written.
+// Vectors are not exported; the importing side embeds prompts with its own embedder.
+func (t *CacheTransfer) Export(ctx context.Context, w io.Writer, filter entity.CacheFilter) (int, error) {
+	enc := json.NewEncoder(w)
+	written, cursor := 0, ""
+	for {
+		page, err := t.vectorStore.List(ctx, filter, exportPageSize, cursor)
+		if err != nil {
+			return written, err
+		}
+		for _, entry := range page.Entries {
+			err := enc.Encode(entity.CacheExport{
+				ID:       entry.ID,
+				Prompt:   entry.Prompt,
+				Content:  entry.Content,
+				Metadata: entry.Metadata,
+			})
+			if err != nil {
+				return written, err
+			}
+			written++
+		}
+		if page.NextCursor == "" {
+			return written, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// PrepareImport builds the cache record for an imported entry exactly as a fresh save would:
+// lexical signature, sparse vector, provenance (defaulting to the current profile), intent
+// metadata (extracted when the line carries none) and vectors from the entry when reusable,
+// otherwise re-embedded. Hit and feedback bookkeeping from the source environment is dropped.
+func (u *Orchestrator) PrepareImport(ctx context.Context, item entity.CacheExport, reuseVector bool) (entity.CacheRecord, bool, error) {
+	var (
+		record     entity.CacheRecord
+		reembedded bool
+	)
+	// The dual layout needs both vectors, which an export line cannot carry
+	if reuseVector && len(item.Vector) > 0 && u.embeddingMode != entity.EmbedModeDual {
+		record = entity.CacheRecord{Prompt: item.Prompt, Vector: item.Vector, Sparse: sparseVector(item.Prompt)}
+	} else {
+		var err error
+		if record, err = u.Reembed(ctx, item.Prompt); err != nil {
+			return entity.CacheRecord{}, false, fmt.Errorf("re-embedding failed: %w", err)
+		}
+		reembedded = true
+	}
+
+	id := item.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+	record.Response = &entity.AIResponse{ID: id, Content: item.Content}
+
+	// Curated entries carry no provenance; stamp them so the compatibility filters match
+	metadata := map[string]any{
+		entity.ProviderKey:        u.profile.Provider,
+		entity.ModelKey:           u.profile.Model,
+		entity.ModelFamilyKey:     entity.ModelFamily(u.profile.Model),
+		entity.OptionsHashKey:     optionsHash(entity.AIRequest{}),
+		entity.TemplateVersionKey: u.profile.TemplateVersion,
+	}
+	if !hasIntent(item.Metadata) {
+		// Curated lines would otherwise never match a lookup scoped by intent
+		for k, v := range u.extractor.ExtractMetadata(ctx, item.Prompt) {
+			metadata[k] = v
+		}
+	}
+	for k, v := range item.Metadata {
+		if !slices.Contains(storeOwnedKeys, k) {
+			metadata[k] = v
+		}
+	}
+	maps.Copy(metadata, lexicalSignature(item.Prompt).Payload())
+	record.Metadata = metadata
+	return record, reembedded, nil
+}
+
+// --- Private Helpers ---
+
+// Payload fields the store maintains for each entry; an import starts them afresh
+var storeOwnedKeys = []string{entity.HitCountKey, entity.LastHitAtKey, "feedback_up", "feedback_down", "feedback_score"}
+
+// Metadata a fresh save records besides the extractor's intent fields
+var nonIntentKeys = []string{
+	"user_id", entity.TenantIDKey, entity.TTLKey,
+	entity.ProviderKey, entity.ModelKey, entity.ModelFamilyKey, entity.OptionsHashKey, entity.TemplateVersionKey,
+	entity.LexNumbersKey, entity.LexEntitiesKey, entity.LexRelationsKey, entity.LexTermsKey,
+}
+
+func hasIntent(meta map[string]any) bool {
+	for k := range meta {
+		if !slices.Contains(nonIntentKeys, k) && !slices.Contains(storeOwnedKeys, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func logImportProgress(s entity.ImportSummary) {
+	log.Printf("[CACHE-IMPORT] %d read, %d imported, %d re-embedded, %d failed", s.Read, s.Imported, s.Reembedded, s.Failed)
+}
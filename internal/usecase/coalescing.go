@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// coalescedResult is what the leader of an in-flight group hands to its followers: the
+// answer as the output policy left it, and the policy's verdict if it withheld it.
+type coalescedResult struct {
+	resp    *entity.AIResponse
+	blocked error
+}
+
+// generateCoalesced makes sure concurrent identical requests (same cache scope,
+// normalized prompt and store directive) share a single provider call. settle runs once
+// per generation inside the shared call: it applies the output policy to the answer and
+// caches and charges it, so that happens even if the caller that started it has gone.
+// Its error (a withheld answer) is every caller's error, returned with their copy.
+// The returned bool is true only for that caller. Every caller gets its own copy.
+// Requests routed to other models (forced or a tenant chain) never share a generation.
+func (u *Orchestrator) generateCoalesced(ctx context.Context, prompt string, models []string, provider repository.AIProvider, scope map[string]string, store bool, settle func(*entity.AIResponse) error) (*entity.AIResponse, bool, error) {
+	leader := false
+	key := coalescingKey(prompt, scope)
+	if !store {
+		// A no-store leader would leave store-wanting followers uncached
+		key = "no-store|" + key
+	}
+	if len(models) > 0 {
+		key = "model=" + strings.Join(models, ",") + "|" + key
+	}
+
+	ch := u.inflight.DoChan(key, func() (any, error) {
+		leader = true
+		// The shared call must not die with the leader's client connection,
+		// otherwise every follower would inherit its cancellation.
+		resp, err := provider.Generate(context.WithoutCancel(ctx), prompt)
+		if err != nil {
+			return nil, err
+		}
+		// Assigned before fan-out so every caller reports the ID the entry will be saved under
+		resp.ID = uuid.NewString()
+		blocked := settle(resp)
+		return &coalescedResult{resp: resp, blocked: blocked}, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, false, res.Err
+		}
+		shared := res.Val.(*coalescedResult)
+		if leader {
+			return ownCopy(shared.resp), true, shared.blocked
+		}
+		return followerCopy(shared.resp), false, shared.blocked
+	}
+}
+
+// ownCopy gives a caller its own response so it can be annotated without racing
+// the other callers sharing the same generation.
+func ownCopy(shared *entity.AIResponse) *entity.AIResponse {
+	cp := *shared
+	cp.Metadata = maps.Clone(shared.Metadata)
+	if cp.Metadata == nil {
+		cp.Metadata = make(map[string]any)
+	}
+	return &cp
+}
+
+// followerCopy is ownCopy for callers that piggy-backed on another's generation.
+// Followers did not consume any tokens themselves: like a cache hit, they are
+// served an answer someone else already paid for.
+func followerCopy(shared *entity.AIResponse) *entity.AIResponse {
+	cp := ownCopy(shared)
+	cp.Metadata["coalesced"] = true
+	cp.Metadata["shared_token_count"] = shared.TokenCount
+	cp.TokenCount = 0
+	cp.Cost = 0
+	return cp
+}
+
+// coalescingKey builds a deterministic key from the cache scope (sorted so map
+// iteration order doesn't matter) and a whitespace-normalized prompt. Case is kept:
+// prompts about code or identifiers can differ in case alone.
+func coalescingKey(prompt string, scope map[string]string) string {
+	keys := make([]string, 0, len(scope))
+	for k := range scope {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(scope[k])
+		b.WriteByte(';')
+	}
+	b.WriteByte('|')
+	b.WriteString(normalizePrompt(prompt))
+	return b.String()
+}
+
+func normalizePrompt(prompt string) string {
+	return strings.Join(strings.Fields(prompt), " ")
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"sentinel-core/internal/domain/entity"
+)
+
+// lookupFilters narrows the cache scope to answers the current model, prompt
+// template and generation options may reuse. Entries cached before provenance was
+// recorded match any of them (see entity.MatchesUnrecorded).
+func (u *Orchestrator) lookupFilters(scope map[string]string, req entity.AIRequest) map[string]string {
+	filters := maps.Clone(scope)
+	filters[entity.OptionsHashKey] = optionsHash(req)
+	if u.profile.TemplateVersion != "" {
+		filters[entity.TemplateVersionKey] = u.profile.TemplateVersion
+	}
+	model := u.answeringModel(req)
+	if model == "" {
+		return filters
+	}
+
+	switch u.compatibility {
+	case entity.CompatSameModel:
+		filters[entity.ModelKey] = model
+	case entity.CompatSameFamily:
+		filters[entity.ModelFamilyKey] = entity.ModelFamily(model)
+	}
+	return filters
+}
+
+// provenance records which model, template and options produced a generated answer.
+func (u *Orchestrator) provenance(req entity.AIRequest, resp *entity.AIResponse) map[string]any {
+	return map[string]any{
+		entity.ProviderKey:        resp.Provider,
+		entity.ModelKey:           resp.Model,
+		entity.ModelFamilyKey:     entity.ModelFamily(resp.Model),
+		entity.OptionsHashKey:     optionsHash(req),
+		entity.TemplateVersionKey: u.profile.TemplateVersion,
+	}
+}
+
+// optionsHash fingerprints the request options that change what the model generates.
+func optionsHash(req entity.AIRequest) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "temperature=%.3f", req.Temperature))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sealed values look like "enc:v1:<data key version>:<base64(nonce || ciphertext)>"
+const sealedPrefix = "enc:v1:"
+
+// How long the current data key version is trusted before the store is asked again,
+// so a rotation on another replica is picked up
+const currentKeyTTL = time.Minute
+
+var encryptionFailures = expvar.NewInt("encryption_failures")
+
+// EnvelopeCipher seals payload fields with AES-256-GCM under per-tenant data keys, which
+// are themselves wrapped by a master key from the KeyManager. The tenant and key version
+// are bound into every ciphertext, so a value moved to another tenant does not open.
+type EnvelopeCipher struct {
+	kms  repository.KeyManager
+	keys repository.DataKeyStore
+
+	mu      sync.RWMutex
+	aeads   map[string]cipher.AEAD // "<tenant>/<version>" -> unwrapped data key
+	current map[string]currentKey  // tenant -> version new values are sealed with
+}
+
+type currentKey struct {
+	version   int
+	fetchedAt time.Time
+}
+
+func NewEnvelopeCipher(kms repository.KeyManager, keys repository.DataKeyStore) *EnvelopeCipher {
+	return &EnvelopeCipher{kms: kms, keys: keys, aeads: make(map[string]cipher.AEAD), current: make(map[string]currentKey)}
+}
+
+func (e *EnvelopeCipher) Seal(ctx context.Context, tenantID string, plaintext []byte) (string, error) {
+	tenantID = cmp.Or(tenantID, entity.DefaultTenant)
+	version, err := e.currentVersion(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	aead, err := e.aead(ctx, tenantID, version)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, additionalData(tenantID, version))
+	return sealedPrefix + strconv.Itoa(version) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (e *EnvelopeCipher) Open(ctx context.Context, tenantID string, value string) ([]byte, bool, error) {
+	rest, ok := strings.CutPrefix(value, sealedPrefix)
+	if !ok {
+		return []byte(value), true, nil // Cached before encryption was enabled
+	}
+	tenantID = cmp.Or(tenantID, entity.DefaultTenant)
+
+	versionStr, encoded, _ := strings.Cut(rest, ":")
+	version, err := strconv.Atoi(versionStr)
+	if err != nil {
+		return nil, false, errors.New("malformed sealed value")
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false, errors.New("malformed sealed value")
+	}
+	aead, err := e.aead(ctx, tenantID, version)
+	if err != nil {
+		encryptionFailures.Add(1)
+		return nil, false, err
+	}
+	if len(raw) < aead.NonceSize() {
+		return nil, false, errors.New("malformed sealed value")
+	}
+	plaintext, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], additionalData(tenantID, version))
+	if err != nil {
+		encryptionFailures.Add(1)
+		return nil, false, fmt.Errorf("failed to decrypt value of tenant %s: %w", tenantID, err)
+	}
+
+	current, err := e.currentVersion(ctx, tenantID)
+	return plaintext, err == nil && version != current, nil
+}
+
+// RotateDataKey starts a new data key version for a tenant. New values are sealed with
+// it; older values stay readable until re-encrypted.
+func (e *EnvelopeCipher) RotateDataKey(ctx context.Context, tenantID string) (entity.DataKey, error) {
+	latest, err := e.keys.Latest(ctx, tenantID)
+	if err != nil && !errors.Is(err, entity.ErrResourceNotFound) {
+		return entity.DataKey{}, err
+	}
+	next := 1
+	if latest != nil {
+		next = latest.Version + 1
+	}
+	key, created, err := e.createDataKey(ctx, tenantID, next)
+	if err != nil {
+		return entity.DataKey{}, err
+	}
+	if !created {
+		return entity.DataKey{}, fmt.Errorf("%w: data key version %d of %s was just created by another rotation", entity.ErrInvalidRequest, next, tenantID)
+	}
+	e.mu.Lock()
+	e.current[tenantID] = currentKey{version: next, fetchedAt: time.Now()}
+	e.mu.Unlock()
+	log.Printf("[ENCRYPTION] Rotated data key of %s to version %d", tenantID, next)
+	return key, nil
+}
+
+// --- Private Helpers ---
+
+// currentVersion is the tenant's newest data key version, created on first use
+func (e *EnvelopeCipher) currentVersion(ctx context.Context, tenantID string) (int, error) {
+	e.mu.RLock()
+	cur, ok := e.current[tenantID]
+	e.mu.RUnlock()
+	if ok && time.Since(cur.fetchedAt) < currentKeyTTL {
+		return cur.version, nil
+	}
+
+	latest, err := e.keys.Latest(ctx, tenantID)
+	if errors.Is(err, entity.ErrResourceNotFound) {
+		if _, _, err := e.createDataKey(ctx, tenantID, 1); err != nil {
+			return 0, err
+		}
+		// Whoever won a concurrent creation, version 1 exists now
+		latest, err = e.keys.Latest(ctx, tenantID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load data key of %s: %w", tenantID, err)
+	}
+
+	e.mu.Lock()
+	e.current[tenantID] = currentKey{version: latest.Version, fetchedAt: time.Now()}
+	e.mu.Unlock()
+	return latest.Version, nil
+}
+
+// aead unwraps a data key version once and keeps it in memory. Keys wrapped by a retired
+// master key are re-wrapped under the current one on the way.
+func (e *EnvelopeCipher) aead(ctx context.Context, tenantID string, version int) (cipher.AEAD, error) {
+	id := tenantID + "/" + strconv.Itoa(version)
+	e.mu.RLock()
+	aead, ok := e.aeads[id]
+	e.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	key, err := e.keys.Get(ctx, tenantID, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data key %s: %w", id, err)
+	}
+	plain, err := e.kms.UnwrapKey(ctx, key.MasterKeyID, key.Wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %s: %w", id, err)
+	}
+	if key.MasterKeyID != e.kms.CurrentKeyID() {
+		e.rewrap(ctx, *key, plain)
+	}
+	aead, err = newAEAD(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.aeads[id] = aead
+	e.mu.Unlock()
+	return aead, nil
+}
+
+func (e *EnvelopeCipher) createDataKey(ctx context.Context, tenantID string, version int) (entity.DataKey, bool, error) {
+	plain := make([]byte, 32)
+	if _, err := rand.Read(plain); err != nil {
+		return entity.DataKey{}, false, err
+	}
+	wrapped, masterKeyID, err := e.kms.WrapKey(ctx, plain)
+	if err != nil {
+		return entity.DataKey{}, false, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	key := entity.DataKey{TenantID: tenantID, Version: version, MasterKeyID: masterKeyID, Wrapped: wrapped, CreatedAt: time.Now().UTC()}
+	created, err := e.keys.Create(ctx, key)
+	if err != nil {
+		return entity.DataKey{}, false, fmt.Errorf("failed to store data key: %w", err)
+	}
+	return key, created, nil
+}
+
+// rewrap moves a data key to the current master key; failures only delay the move
+func (e *EnvelopeCipher) rewrap(ctx context.Context, key entity.DataKey, plain []byte) {
+	wrapped, masterKeyID, err := e.kms.WrapKey(ctx, plain)
+	if err == nil {
+		key.Wrapped, key.MasterKeyID = wrapped, masterKeyID
+		err = e.keys.Save(ctx, key)
+	}
+	if err != nil {
+		log.Printf("[ENCRYPTION] Failed to re-wrap data key %s/%d: %v", key.TenantID, key.Version, err)
+		return
+	}
+	log.Printf("[ENCRYPTION] Re-wrapped data key %s/%d under master key %s", key.TenantID, key.Version, masterKeyID)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// additionalData binds a ciphertext to its tenant and key version
+func additionalData(tenantID string, version int) []byte {
+	return []byte("sentinel:" + tenantID + ":" + strconv.Itoa(version))
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"time"
+)
+
+// FeedbackService records user verdicts on responses and demotes the cache
+// entries behind badly rated answers.
+type FeedbackService struct {
+	vectorStore repository.VectorStore
+	events      repository.FeedbackRepository
+	tenants     *TenantService // Optional; without it every tenant caches per user
+}
+
+func NewFeedbackService(vs repository.VectorStore, events repository.FeedbackRepository, tenants *TenantService) *FeedbackService {
+	return &FeedbackService{vectorStore: vs, events: events, tenants: tenants}
+}
+
+func (s *FeedbackService) Submit(ctx context.Context, fb entity.Feedback) (entity.FeedbackTally, error) {
+	// 1. Validation
+	if fb.ResponseID == "" || fb.UserID == "" {
+		return entity.FeedbackTally{}, fmt.Errorf("%w: response_id and user_id are required", entity.ErrInvalidRequest)
+	}
+	if fb.Rating != entity.RatingUp && fb.Rating != entity.RatingDown {
+		return entity.FeedbackTally{}, fmt.Errorf("%w: rating must be %q or %q", entity.ErrInvalidRequest, entity.RatingUp, entity.RatingDown)
+	}
+	fb.Timestamp = time.Now()
+
+	// 2. Only callers the entry could have been served to may rate it
+	entry, err := s.vectorStore.Get(ctx, fb.ResponseID)
+	if err != nil {
+		return entity.FeedbackTally{}, err
+	}
+	fb.TenantID = cmp.Or(fb.TenantID, entity.DefaultTenant)
+	servable, err := s.servable(ctx, entry, fb)
+	if err != nil {
+		return entity.FeedbackTally{}, err
+	}
+	if !servable {
+		return entity.FeedbackTally{}, entity.ErrResourceNotFound
+	}
+
+	// 3. One vote per user and response: a re-vote replaces the earlier one
+	previous, err := s.events.SwapVote(ctx, fb)
+	if err != nil {
+		return entity.FeedbackTally{}, err
+	}
+
+	// 4. Update the tally that Search uses to exclude demoted entries
+	tally, err := s.vectorStore.ApplyFeedback(ctx, fb.ResponseID, entity.Vote{Rating: fb.Rating, Previous: previous})
+	if err != nil {
+		// Put the earlier vote back, or voting again would count as a re-vote of one never tallied
+		undo := fb
+		undo.Rating = previous
+		if _, undoErr := s.events.SwapVote(ctx, undo); undoErr != nil {
+			log.Printf("[FEEDBACK] Failed to restore the vote of %s on %s: %v", fb.UserID, fb.ResponseID, undoErr)
+		}
+		return entity.FeedbackTally{}, err
+	}
+
+	// 5. Persist the raw event for later analysis; the vote itself already counted
+	if err := s.events.Record(ctx, fb); err != nil {
+		log.Printf("[FEEDBACK] Failed to persist feedback event for %s: %v", fb.ResponseID, err)
+	}
+
+	return tally, nil
+}
+
+// --- Private Helpers ---
+
+// servable mirrors the lookup scope: the caller's tenant, and within it the caller's own
+// entries, shared ones without a user, or every entry when the tenant shares its cache.
+func (s *FeedbackService) servable(ctx context.Context, entry *entity.CacheEntry, fb entity.Feedback) (bool, error) {
+	if tenant, _ := entry.Metadata[entity.TenantIDKey].(string); cmp.Or(tenant, entity.DefaultTenant) != fb.TenantID {
+		return false, nil
+	}
+	if owner, _ := entry.Metadata["user_id"].(string); owner == "" || owner == fb.UserID {
+		return true, nil
+	}
+	if s.tenants == nil {
+		return false, nil
+	}
+	tenant, err := s.tenants.Resolve(ctx, fb.TenantID)
+	if errors.Is(err, entity.ErrUnknownTenant) {
+		return false, nil // Deleted since; nothing is served from its cache any more
+	}
+	if err != nil {
+		return false, err
+	}
+	return tenant.CacheScope == entity.CacheScopeTenant, nil
+}
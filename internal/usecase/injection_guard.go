@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"strings"
+)
+
+// Guard decisions per action, published on the admin /metrics endpoint
+var injectionDecisions = expvar.NewMap("injection_decisions")
+
+// InjectionGuard scores prompts for injection and jailbreak attempts with one or more
+// classifiers (cheap heuristics first, an optional model after) and decides whether to
+// allow, flag or block them. The overall risk is the highest classifier risk.
+type InjectionGuard struct {
+	classifiers []repository.InjectionClassifier
+	flagAt      float64
+	blockAt     float64
+}
+
+func NewInjectionGuard(flagAt, blockAt float64, classifiers ...repository.InjectionClassifier) *InjectionGuard {
+	return &InjectionGuard{classifiers: classifiers, flagAt: flagAt, blockAt: blockAt}
+}
+
+// Assess runs the classifiers in order, stopping as soon as one blocks the prompt.
+// A failing classifier is skipped rather than failing the request.
+func (g *InjectionGuard) Assess(ctx context.Context, prompt string) entity.InjectionAssessment {
+	assessment := entity.InjectionAssessment{Action: entity.InjectionAllow}
+	for _, c := range g.classifiers {
+		score, err := c.Classify(ctx, prompt)
+		if err != nil {
+			log.Printf("[GUARD] Injection classifier failed: %v", err)
+			continue
+		}
+		if score.Risk >= g.flagAt {
+			assessment.Signals = append(assessment.Signals, score.Signals...)
+		}
+		assessment.Risk = max(assessment.Risk, score.Risk)
+		if assessment.Risk >= g.blockAt {
+			break
+		}
+	}
+
+	switch {
+	case assessment.Risk >= g.blockAt:
+		assessment.Action = entity.InjectionBlock
+	case assessment.Risk >= g.flagAt:
+		assessment.Action = entity.InjectionFlag
+	}
+	injectionDecisions.Add(string(assessment.Action), 1)
+	return assessment
+}
+
+// screen applies the guard: blocked prompts fail the request, flagged ones are answered
+// without touching the cache so they can neither force a hit nor poison an entry.
+func (u *Orchestrator) screen(ctx context.Context, req *entity.AIRequest) (entity.InjectionAssessment, error) {
+	if u.guard == nil {
+		return entity.InjectionAssessment{Action: entity.InjectionAllow}, nil
+	}
+	assessment := u.guard.Assess(ctx, req.Prompt)
+	switch assessment.Action {
+	case entity.InjectionBlock:
+		log.Printf("[GUARD] Blocked prompt from %s (risk %.2f: %s)", req.UserID, assessment.Risk, strings.Join(assessment.Signals, ","))
+		return assessment, fmt.Errorf("%w (risk %.2f)", entity.ErrPromptInjection, assessment.Risk)
+	case entity.InjectionFlag:
+		req.Cache.NoCache = true
+		req.Cache.NoStore = true
+	}
+	return assessment, nil
+}
+
+// annotateInjection reports a flagged prompt in the response metadata.
+func annotateInjection(resp *entity.AIResponse, assessment entity.InjectionAssessment) {
+	if assessment.Action == entity.InjectionAllow {
+		return
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]any)
+	}
+	resp.Metadata["injection_risk"] = assessment.Risk
+	resp.Metadata["injection_signals"] = assessment.Signals
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"hash/fnv"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"sentinel-core/internal/domain/entity"
+)
+
+var (
+	// Digits with optional thousands separators, decimals, dates and times: 1,000.50 / 2024-01-31 / 10:30
+	numberPattern = regexp.MustCompile(`\d(?:[\d,./:-]*\d)?`)
+	// "quoted terms" are treated as entities regardless of casing
+	quotedPattern = regexp.MustCompile(`["“']([^"”']{2,})["”']`)
+	// Directional roles; the last captured word is the participant
+	relationPattern = regexp.MustCompile(`(?i)\b(from|to|into|for|between)\s+(?:(the|my|account)\s+)?([\p{L}\p{N}_-]+)`)
+	// A source or pair in the prompt makes "to"/"for" a direction rather than an infinitive
+	directionPattern = regexp.MustCompile(`(?i)\b(from|between)\s`)
+	wordPattern      = regexp.MustCompile(`[\p{L}\p{N}_-]+`)
+)
+
+// Capitalized words that say nothing about the entity being asked about
+var entityStopwords = map[string]bool{
+	"i": true, "a": true, "an": true, "the": true, "what": true, "who": true, "how": true,
+	"why": true, "when": true, "where": true, "which": true, "can": true, "please": true,
+	"is": true, "are": true, "do": true, "does": true, "my": true,
+}
+
+// BM25 parameters for the sparse representation; IDF is applied by the vector store.
+const (
+	bm25K1         = 1.2
+	bm25B          = 0.75
+	bm25AvgDocLen  = 12.0 // Typical prompt length in tokens
+	sparseHashBits = 0x7FFFFFFF
+)
+
+// lexicalSignature extracts the critical tokens the hybrid guard compares.
+func lexicalSignature(prompt string) entity.LexicalSignature {
+	sig := entity.LexicalSignature{}
+
+	for _, n := range numberPattern.FindAllString(prompt, -1) {
+		sig.Numbers = append(sig.Numbers, normalizeNumber(n))
+	}
+	slices.Sort(sig.Numbers)
+
+	entities := make(map[string]bool)
+	for _, m := range quotedPattern.FindAllStringSubmatch(prompt, -1) {
+		entities[strings.ToLower(strings.TrimSpace(m[1]))] = true
+	}
+	for _, sentence := range splitSentences(prompt) {
+		words := wordPattern.FindAllString(sentence, -1)
+		// Skip the first word of each sentence: it is capitalized by grammar, not by name
+		for i := 1; i < len(words); i++ {
+			w := words[i]
+			lw := strings.ToLower(w)
+			if unicode.IsUpper([]rune(w)[0]) && !entityStopwords[lw] {
+				entities[lw] = true
+			}
+		}
+	}
+	sig.Entities = sortedKeys(entities)
+
+	relations := make(map[string]bool)
+	directional := directionPattern.MatchString(prompt)
+	for _, m := range relationPattern.FindAllStringSubmatch(prompt, -1) {
+		role, participant := strings.ToLower(m[1]), m[3]
+		if !directional && (role == "to" || role == "for") && m[2] == "" && !isParticipant(participant, entities) {
+			continue // "how to reset", "for example": not a direction
+		}
+		if role == "into" {
+			role = "to"
+		}
+		relations[role+":"+normalizeNumber(strings.ToLower(participant))] = true
+	}
+	sig.Relations = sortedKeys(relations)
+
+	terms := make(map[string]bool)
+	for _, w := range wordPattern.FindAllString(strings.ToLower(prompt), -1) {
+		terms[w] = true
+	}
+	sig.Terms = sortedKeys(terms)
+
+	return sig
+}
+
+// sparseVector builds a hashed BM25-style term-frequency vector for the prompt.
+func sparseVector(prompt string) *entity.SparseVector {
+	tokens := wordPattern.FindAllString(strings.ToLower(prompt), -1)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	tf := make(map[uint32]float64)
+	for _, tok := range tokens {
+		h := fnv.New32a()
+		h.Write([]byte(tok))
+		tf[h.Sum32()&sparseHashBits]++
+	}
+
+	norm := bm25K1 * (1 - bm25B + bm25B*float64(len(tokens))/bm25AvgDocLen)
+	vec := &entity.SparseVector{}
+	for _, idx := range slices.Sorted(maps.Keys(tf)) {
+		f := tf[idx]
+		vec.Indices = append(vec.Indices, idx)
+		vec.Values = append(vec.Values, float32(math.Round(f*(bm25K1+1)/(f+norm)*1e4)/1e4))
+	}
+	return vec
+}
+
+// --- Private Helpers ---
+
+// isParticipant tells a named or numbered participant ("to Alice", "for 2024") from a verb or filler
+func isParticipant(word string, entities map[string]bool) bool {
+	return strings.ContainsFunc(word, unicode.IsDigit) || unicode.IsUpper([]rune(word)[0]) || entities[strings.ToLower(word)]
+}
+
+// normalizeNumber strips formatting that doesn't change the value: "1,000" == "1000"
+func normalizeNumber(n string) string {
+	return strings.ReplaceAll(n, ",", "")
+}
+
+func splitSentences(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == '.' || r == '?' || r == '!' || r == '\n'
+	})
+}
+
+func sortedKeys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	slices.Sort(out)
+	return out
+}
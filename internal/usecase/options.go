@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"time"
+)
+
+// Option tunes optional Orchestrator behaviour; the defaults match the original gateway.
+type Option func(*Orchestrator)
+
+// WithFreshness sets how long a cached answer is served as fresh.
+func WithFreshness(d time.Duration) Option {
+	return func(u *Orchestrator) {
+		if d > 0 {
+			u.freshness = d
+		}
+	}
+}
+
+// WithStaleWhileRevalidate lets entries up to maxStaleness past the freshness window
+// be served immediately while at most maxRefreshes background regenerations run.
+func WithStaleWhileRevalidate(maxStaleness time.Duration, maxRefreshes int) Option {
+	return func(u *Orchestrator) {
+		if maxStaleness <= 0 || maxRefreshes <= 0 {
+			return
+		}
+		u.maxStaleness = maxStaleness
+		u.refreshSlots = make(chan struct{}, maxRefreshes)
+	}
+}
+
+// WithCandidateReranking sets how many nearest neighbours a lookup retrieves and
+// how many of them the evaluator may judge before the lookup counts as a miss.
+func WithCandidateReranking(topK, judgeBudget int) Option {
+	return func(u *Orchestrator) {
+		if topK > 0 {
+			u.topK = topK
+		}
+		if judgeBudget >= 0 {
+			u.judgeBudget = judgeBudget
+		}
+	}
+}
+
+// WithCacheCompatibility records the generation profile on saved answers and only
+// serves cached answers compatible with it under the given policy.
+func WithCacheCompatibility(profile entity.GenerationProfile, policy entity.CompatibilityPolicy) Option {
+	return func(u *Orchestrator) {
+		u.profile = profile
+		u.compatibility = policy
+	}
+}
+
+// WithEmbeddingMode selects which embedding task types are stored and searched with.
+func WithEmbeddingMode(mode entity.EmbeddingMode) Option {
+	return func(u *Orchestrator) {
+		u.embeddingMode = mode
+	}
+}
+
+// WithRedaction masks PII in prompts before any outbound call or cache write.
+func WithRedaction(r *Redactor) Option {
+	return func(u *Orchestrator) {
+		u.redactor = r
+	}
+}
+
+// WithInjectionGuard screens prompts for injection attempts before any model call.
+func WithInjectionGuard(g *InjectionGuard) Option {
+	return func(u *Orchestrator) {
+		u.guard = g
+	}
+}
+
+// WithOutputPolicy checks every answer against the policy before it is served or cached.
+func WithOutputPolicy(p *OutputPolicy) Option {
+	return func(u *Orchestrator) {
+		u.outputPolicy = p
+	}
+}
+
+// WithRules evaluates the request rules on every request. The redactor serves rules
+// forcing redaction when PII redaction is otherwise off.
+func WithRules(engine *RuleEngine, redactor *Redactor) Option {
+	return func(u *Orchestrator) {
+		u.rules = engine
+		u.ruleRedactor = redactor
+	}
+}
+
+// WithModels registers the providers a rule may force or a tenant chain may name, by model.
+func WithModels(models map[string]repository.AIProvider) Option {
+	return func(u *Orchestrator) {
+		u.models = models
+	}
+}
+
+// WithTenants resolves the tenant of every request and applies its configuration;
+// requests naming an unknown tenant are rejected.
+func WithTenants(s *TenantService) Option {
+	return func(u *Orchestrator) {
+		u.tenants = s
+	}
+}
+
+// WithTenantRedaction provides the masking and tokenizing redactors tenants may choose.
+func WithTenantRedaction(masker, tokener *Redactor) Option {
+	return func(u *Orchestrator) {
+		u.masker = masker
+		u.tokener = tokener
+	}
+}
+
+// WithAudit records an audit event for every request.
+func WithAudit(a *Auditor) Option {
+	return func(u *Orchestrator) {
+		u.auditor = a
+	}
+}
@@ -1,10 +1,24 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"log"
+	"maps"
 	"sentinel-core/internal/domain/entity"
 	"sentinel-core/internal/domain/repository"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultFreshness   = 24 * time.Hour
+	defaultTopK        = 5 // Candidates retrieved per lookup
+	defaultJudgeBudget = 3 // Max evaluator calls per lookup
 )
 
 type Orchestrator struct {
@@ -14,89 +28,392 @@ type Orchestrator struct {
 	embedder     repository.Embedder
 	evaluator    repository.Evaluator
 	extractor    repository.Extractor
+
+	// inflight coalesces identical concurrent generations (see coalescing.go)
+	inflight singleflight.Group
+
+	// Cache freshness and stale-while-revalidate (see revalidate.go)
+	freshness    time.Duration
+	maxStaleness time.Duration
+	refreshSlots chan struct{} // nil when stale-while-revalidate is disabled
+	refreshing   sync.Map      // entry IDs with a refresh in flight
+
+	// Candidate retrieval and reranking
+	topK        int
+	judgeBudget int
+
+	// Which cached answers the current model/template may reuse (see compatibility.go)
+	profile       entity.GenerationProfile
+	compatibility entity.CompatibilityPolicy
+
+	// Which embeddings are stored and searched with
+	embeddingMode entity.EmbeddingMode
+
+	// PII masking before any outbound call or cache write (see redaction.go); nil disables it
+	redactor *Redactor
+
+	// Prompt-injection screening (see injection_guard.go); nil disables it
+	guard *InjectionGuard
+
+	// Post-generation checks on answers (see output_policy.go); nil disables it
+	outputPolicy *OutputPolicy
+
+	// Declarative request rules (see request_rules.go); nil disables them
+	rules        *RuleEngine
+	ruleRedactor *Redactor                        // Used when a rule forces redaction and PII redaction is off
+	models       map[string]repository.AIProvider // Models a rule may force or a tenant chain may name
+
+	// Tenant resolution (see tenants.go); nil serves every request as its named tenant
+	// with the gateway defaults
+	tenants *TenantService
+	masker  *Redactor // Redaction a tenant may choose over the gateway's
+	tokener *Redactor
+
+	// Audit trail of every request (see audit.go); nil disables it
+	auditor *Auditor
 }
 
-func NewOrchestrator(vs repository.VectorStore, tl repository.TokenLimiter, ai repository.AIProvider, emb repository.Embedder, ev repository.Evaluator, ex repository.Extractor) *Orchestrator {
-	return &Orchestrator{vectorStore: vs, tokenLimiter: tl, aiProvider: ai, embedder: emb, evaluator: ev, extractor: ex}
+func NewOrchestrator(vs repository.VectorStore, tl repository.TokenLimiter, ai repository.AIProvider, emb repository.Embedder, ev repository.Evaluator, ex repository.Extractor, opts ...Option) *Orchestrator {
+	u := &Orchestrator{
+		vectorStore: vs, tokenLimiter: tl, aiProvider: ai, embedder: emb, evaluator: ev, extractor: ex,
+		freshness: defaultFreshness, topK: defaultTopK, judgeBudget: defaultJudgeBudget, compatibility: entity.CompatSameModel, embeddingMode: entity.EmbedModeQuery,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
-func (u *Orchestrator) Execute(ctx context.Context, req entity.AIRequest) (*entity.AIResponse, error) {
+func (u *Orchestrator) Execute(ctx context.Context, req entity.AIRequest) (resp *entity.AIResponse, err error) {
+	// Every request leaves an audit event, however far it gets
+	started, prompt := time.Now(), req.Prompt
+	event := &entity.AuditEvent{Cache: entity.AuditCacheNone}
+	defer func() { u.audit(event, req, prompt, resp, err, started) }()
+
+	// 0. Tenancy: the tenant's configuration governs every later step
+	if err := u.resolveTenant(ctx, &req); err != nil {
+		return nil, err
+	}
+
 	// 1. Guard Rail: Rate Limiting
-	if err := u.validateRateLimit(ctx, req.UserID); err != nil {
+	if err := u.validateRateLimit(ctx, usageScope(req)); err != nil {
 		return nil, err
 	}
 
-	// 2. Pre-processing: Metadata & Embeddings
+	// 2. Policy Rules: denied requests stop before any outbound call
+	if err := u.applyRules(&req, prompt, nil, false); err != nil {
+		return nil, err
+	}
+
+	// 3. Privacy: mask PII before the prompt leaves the gateway or reaches the cache
+	redaction := u.redact(&req)
+	redacted := u.redactorFor(req) != nil || req.Policy.ForceRedaction
+	event.Redacted = redaction.Counts
+
+	// 4. Security: blocked prompts never reach a model or the cache
+	assessment, err := u.screen(ctx, &req)
+	event.InjectionRisk = assessment.Risk
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Pre-processing: Metadata, intent rules & Embeddings
 	extractedMeta := u.extractor.ExtractMetadata(ctx, req.Prompt)
-	vector, err := u.embedder.CreateEmbedding(ctx, req.Prompt)
+	if err := u.applyRules(&req, prompt, extractedMeta, true); err != nil {
+		return nil, err
+	}
+	if err := u.authorizeModel(req); err != nil {
+		return nil, err
+	}
+	if req.Policy.ForceRedaction && !redacted {
+		// Intent-triggered: the extractor (and a model injection classifier) already saw the
+		// original prompt. Rules without metadata conditions redact in step 3 instead
+		redaction = u.redact(&req)
+		event.Redacted = redaction.Counts
+	}
+	vector, err := u.embedder.CreateEmbeddingFor(ctx, req.Prompt, u.embeddingMode.LookupTask())
 	if err != nil {
 		return nil, fmt.Errorf("embedding failed: %w", err)
 	}
 
-	// 3. Cache Strategy: Try to find an existing answer
-	if cachedResp := u.tryGetCachedResponse(ctx, req.Prompt, req.UserID, vector, extractedMeta); cachedResp != nil {
-		return cachedResp, nil
+	// 6. Cache Strategy: Try to find an existing answer (unless the caller opted out)
+	scope := u.lookupFilters(cacheScope(req, extractedMeta), req)
+	event.Cache = entity.AuditCacheBypass
+	if !req.Cache.SkipLookup() {
+		event.Cache = entity.AuditCacheMiss
+		if hit := u.tryGetCachedResponse(ctx, req.Prompt, vector, scope, req.Cache.MaxAgeDuration()); hit != nil {
+			go u.recordHit(hit.Response.ID)
+			event.Cache = entity.AuditCacheHit
+
+			// Entries cached before a rule was added are held to it too
+			if _, err := u.enforcePolicy(hit.Response, extractedMeta); err != nil {
+				return nil, err
+			}
+
+			// Stale hits are served now and regenerated in the background
+			if age := hit.Age(); age > u.freshness {
+				markStale(hit.Response, age)
+				event.Cache = entity.AuditCacheStale
+				if !req.Cache.NoStore {
+					u.scheduleRefresh(req, hit.Response.ID, vector, extractedMeta)
+				}
+			}
+			hit.Response.CachePolicy = req.Cache.String()
+			annotateRedaction(hit.Response, redaction)
+			annotateRules(hit.Response, req.Policy)
+			return rehydrate(hit.Response, redaction), nil
+		}
 	}
 
-	// 4. Provider Strategy: Generate new answer
-	resp, err := u.aiProvider.Generate(ctx, req.Prompt)
+	// 7. Provider Strategy: Generate new answer (shared with identical in-flight requests)
+	// 8. Output Policy: applied once to the shared generation, which is cached and charged
+	// along with it (see settleGeneration); violating answers are fixed or withheld
+	settle := func(shared *entity.AIResponse) error { return u.settleGeneration(req, shared, vector, extractedMeta) }
+	resp, leader, err := u.generateCoalesced(ctx, req.Prompt, modelChain(req), u.providerFor(req), scope, !req.Cache.NoStore, settle)
+	if resp != nil && !leader {
+		event.Cache = entity.AuditCacheCoalesced
+	}
 	if err != nil {
+		if resp != nil && leader {
+			event.TokenCount, event.Cost = resp.TokenCount, resp.Cost
+		}
 		return nil, err
 	}
+	resp.CachePolicy = req.Cache.String()
+	annotateRedaction(resp, redaction)
+	annotateInjection(resp, assessment)
+	annotateRules(resp, req.Policy)
 
-	// 5. Post-processing: Async updates
-	u.asyncBackgroundUpdate(req, resp, vector, extractedMeta)
-
-	return resp, nil
+	// Only the caller's copy gets its PII back; the cache keeps the tokens
+	return rehydrate(resp, redaction), nil
 }
 
 // --- Private Helpers ---
 
-func (u *Orchestrator) validateRateLimit(ctx context.Context, userID string) error {
-	allowed, err := u.tokenLimiter.CheckLimit(ctx, userID)
+// authorizeModel keeps callers to the models their credentials and tenant allow: the
+// one they asked for and the one that would answer, and to the tenant's providers.
+func (u *Orchestrator) authorizeModel(req entity.AIRequest) error {
+	for _, model := range []string{req.Model, u.answeringModel(req)} {
+		if model != "" && (!entity.AllowsModel(req.AllowedModels, model) || !entity.AllowsModel(req.Tenant.AllowedModels, model)) {
+			return fmt.Errorf("%w: %s", entity.ErrModelNotAllowed, model)
+		}
+	}
+	if provider := cmp.Or(req.Provider, u.profile.Provider); provider != "" && !req.Tenant.AllowsProvider(provider) {
+		return fmt.Errorf("%w: provider %s", entity.ErrModelNotAllowed, provider)
+	}
+	return nil
+}
+
+// modelChain lists the models a request is answered by, in fallback order: a model
+// forced by the rules, then the tenant's fallback chain. Empty means the default provider.
+func modelChain(req entity.AIRequest) []string {
+	chain := req.Tenant.FallbackChain
+	if forced := req.Policy.ForceModel; forced != "" {
+		rest := slices.DeleteFunc(slices.Clone(chain), func(m string) bool { return m == forced })
+		chain = append([]string{forced}, rest...)
+	}
+	return chain
+}
+
+// answeringModel is the model expected to answer a request.
+func (u *Orchestrator) answeringModel(req entity.AIRequest) string {
+	if chain := modelChain(req); len(chain) > 0 {
+		return chain[0]
+	}
+	return u.profile.Model
+}
+
+// providerFor builds the provider of a request's model chain. A forced model without a
+// tenant chain falls back to the default provider; a tenant chain replaces it.
+func (u *Orchestrator) providerFor(req entity.AIRequest) repository.AIProvider {
+	var chain []repository.AIProvider
+	for _, model := range modelChain(req) {
+		if p, ok := u.models[model]; ok {
+			chain = append(chain, p)
+		}
+	}
+	if len(chain) == 0 || (len(req.Tenant.FallbackChain) == 0 && req.Policy.ForceModel == u.profile.Model) {
+		return u.aiProvider
+	}
+	if len(req.Tenant.FallbackChain) == 0 {
+		chain = append(chain, u.aiProvider)
+	}
+	return NewResilientChain(chain[0], chain[1:]...)
+}
+
+func (u *Orchestrator) validateRateLimit(ctx context.Context, scope entity.UsageScope) error {
+	allowed, err := u.tokenLimiter.CheckLimit(ctx, scope)
 	if err != nil || !allowed {
 		return entity.ErrRateLimitExceeded
 	}
 	return nil
 }
 
-func (u *Orchestrator) tryGetCachedResponse(ctx context.Context, prompt, userID string, vector []float32, meta map[string]string) *entity.AIResponse {
-	// Prepare scoped filters (User ID + Extracted Intent)
-	filters := map[string]string{"user_id": userID}
+// cacheScope prepares the scoped filters (Tenant + User ID + Extracted Intent). Answers
+// never cross tenants; within one they are per user unless the tenant shares them.
+func cacheScope(req entity.AIRequest, meta map[string]string) map[string]string {
+	filters := make(map[string]string, len(meta)+2)
 	for k, v := range meta {
 		filters[k] = v
 	}
+	filters[entity.TenantIDKey] = req.TenantID
+	if req.Tenant.CacheScope != entity.CacheScopeTenant {
+		filters["user_id"] = req.UserID
+	}
+	return filters
+}
+
+func (u *Orchestrator) tryGetCachedResponse(ctx context.Context, prompt string, vector []float32, filters map[string]string, maxAge time.Duration) *entity.CacheHit {
+	// The caller's max-age can only narrow the window, never widen it
+	window := u.searchWindow()
+	if maxAge > 0 && maxAge < window {
+		window = maxAge
+	}
 
-	cachedResp, score, originalPrompt, err := u.vectorStore.Search(ctx, vector, 0.75, filters)
-	if err != nil || cachedResp == nil {
+	candidates, err := u.vectorStore.Search(ctx, entity.SearchQuery{
+		Vector:    vector,
+		Threshold: 0.75,
+		Filters:   filters,
+		MaxAge:    window,
+		Limit:     u.topK,
+	})
+	if err != nil || len(candidates) == 0 {
 		return nil
 	}
 
-	// TIER 1: Instant Hit
-	if score > 0.98 {
-		cachedResp.Cached = true
-		return cachedResp
+	// Lexical guard: numbers, entities and direction must agree whatever the score or judge say
+	sig := lexicalSignature(prompt)
+	judged := 0
+	for i := range candidates {
+		hit := &candidates[i]
+		if hit.Expired() || !sig.Agrees(candidateSignature(hit)) {
+			continue
+		}
+
+		// TIER 1: Instant Hit
+		if hit.Score > 0.98 {
+			return acceptHit(hit, i)
+		}
+
+		// TIER 2: Human-like evaluation (Judge), nearest first until the judge budget runs out
+		if judged >= u.judgeBudget {
+			break
+		}
+		judged++
+		if u.evaluator.IsMatch(ctx, prompt, hit.Prompt) {
+			return acceptHit(hit, i)
+		}
+	}
+
+	return nil
+}
+
+// candidateSignature prefers the stored signature and falls back to recomputing it
+// for entries saved before signatures existed. Relations are always recomputed: older
+// entries stored infinitives ("to:reset") as directions.
+func candidateSignature(hit *entity.CacheHit) entity.LexicalSignature {
+	fresh := lexicalSignature(hit.Prompt)
+	if sig, ok := entity.LexicalSignatureFromPayload(hit.Payload); ok {
+		sig.Relations = fresh.Relations
+		return sig
 	}
+	return fresh
+}
 
-	// TIER 2: Human-like evaluation (Judge)
-	if u.evaluator.IsMatch(ctx, prompt, originalPrompt) {
-		cachedResp.Cached = true
-		return cachedResp
+func acceptHit(hit *entity.CacheHit, rank int) *entity.CacheHit {
+	hit.Response.Cached = true
+	if rank > 0 {
+		hit.Response.Metadata = map[string]any{"candidate_rank": rank}
 	}
+	return hit
+}
 
+// settleGeneration applies the output policy to a fresh answer, then caches it and charges
+// its tokens, once per generation. Coalesced callers share the scope and so the intent the
+// policy is evaluated against. Answers the policy fixes or withholds are charged but never
+// cached; a withheld answer is the returned error.
+func (u *Orchestrator) settleGeneration(req entity.AIRequest, resp *entity.AIResponse, vector []float32, meta map[string]string) error {
+	clean, err := u.enforcePolicy(resp, meta)
+	if err != nil || !clean || req.Cache.NoStore {
+		go u.chargeTokens(usageScope(req), resp.TokenCount)
+		return err
+	}
+	// The save outlives the shared call; it gets a copy no caller annotates
+	u.asyncBackgroundUpdate(req, ownCopy(resp), vector, meta)
 	return nil
 }
 
 func (u *Orchestrator) asyncBackgroundUpdate(req entity.AIRequest, resp *entity.AIResponse, vector []float32, meta map[string]string) {
-	go func() {
-		bgCtx := context.Background()
-		saveMeta := make(map[string]any)
-		for k, v := range meta {
-			saveMeta[k] = v
-		}
-		saveMeta["user_id"] = req.UserID
+	go u.backgroundUpdate(req, resp, vector, meta)
+}
+
+func (u *Orchestrator) backgroundUpdate(req entity.AIRequest, resp *entity.AIResponse, vector []float32, meta map[string]string) {
+	bgCtx := context.Background()
+	saveMeta := make(map[string]any)
+	for k, v := range meta {
+		saveMeta[k] = v
+	}
+	saveMeta["user_id"] = req.UserID
+	saveMeta[entity.TenantIDKey] = req.TenantID
+	maps.Copy(saveMeta, lexicalSignature(req.Prompt).Payload())
+	maps.Copy(saveMeta, u.provenance(req, resp))
+	if ttl := cmp.Or(req.Policy.TTL(), req.Tenant.CacheTTL()); ttl > 0 {
+		saveMeta[entity.TTLKey] = int64(ttl.Seconds())
+	}
+
+	record := entity.CacheRecord{
+		Prompt:   req.Prompt,
+		Response: resp,
+		Vector:   vector,
+		Sparse:   sparseVector(req.Prompt),
+		Metadata: saveMeta,
+	}
+	if err := u.attachDocumentVector(bgCtx, &record); err != nil {
+		log.Printf("[SENTINEL] Skipping cache save, document embedding failed: %v", err)
+	} else {
+		_ = u.vectorStore.Save(bgCtx, record)
+	}
+	u.chargeTokens(usageScope(req), resp.TokenCount)
+}
+
+// Reembed rebuilds the vectors of a cached prompt exactly as a fresh save would,
+// for moving the cache to a new embedding model or layout.
+func (u *Orchestrator) Reembed(ctx context.Context, prompt string) (entity.CacheRecord, error) {
+	vector, err := u.embedder.CreateEmbeddingFor(ctx, prompt, u.embeddingMode.LookupTask())
+	if err != nil {
+		return entity.CacheRecord{}, err
+	}
+	record := entity.CacheRecord{Prompt: prompt, Vector: vector, Sparse: sparseVector(prompt)}
+	if err := u.attachDocumentVector(ctx, &record); err != nil {
+		return entity.CacheRecord{}, err
+	}
+	return record, nil
+}
+
+// attachDocumentVector embeds the prompt as a document when the embedding mode stores one.
+func (u *Orchestrator) attachDocumentVector(ctx context.Context, record *entity.CacheRecord) error {
+	if !u.embeddingMode.NeedsDocumentVector() {
+		return nil
+	}
+	doc, err := u.embedder.CreateEmbeddingFor(ctx, record.Prompt, entity.TaskRetrievalDocument)
+	if err != nil {
+		return err
+	}
+	if u.embeddingMode == entity.EmbedModeDual {
+		record.DocumentVector = doc
+	} else {
+		record.Vector = doc
+	}
+	return nil
+}
+
+// recordHit feeds the eviction policies; a lost update only nudges eviction order
+func (u *Orchestrator) recordHit(id string) {
+	if err := u.vectorStore.RecordHit(context.Background(), id); err != nil {
+		log.Printf("[SENTINEL] Failed to record cache hit for %s: %v", id, err)
+	}
+}
 
-		_ = u.vectorStore.Save(bgCtx, req.Prompt, resp, vector, saveMeta)
-		_ = u.tokenLimiter.Increment(bgCtx, req.UserID, resp.TokenCount)
-	}()
+func (u *Orchestrator) chargeTokens(scope entity.UsageScope, tokens int) {
+	_ = u.tokenLimiter.Increment(context.Background(), scope, tokens)
 }
@@ -0,0 +1,128 @@
+package usecase_test
+
+import (
+	"context"
+	"sentinel-core/internal/adapter/store"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/usecase"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Pipeline fakes: every prompt embeds to the same vector, so only the cache scope and
+// the lexical guard decide whether a cached answer is served.
+type fakeProvider struct{ calls atomic.Int32 }
+
+func (p *fakeProvider) Generate(_ context.Context, prompt string) (*entity.AIResponse, error) {
+	p.calls.Add(1)
+	return &entity.AIResponse{Content: "answer to " + prompt, Model: "test-model", TokenCount: 10}, nil
+}
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) CreateEmbedding(context.Context, string) ([]float32, error) {
+	return []float32{1, 0, 0}, nil
+}
+
+func (fakeEmbedder) CreateEmbeddingFor(context.Context, string, entity.EmbeddingTask) ([]float32, error) {
+	return []float32{1, 0, 0}, nil
+}
+
+type fakeLimiter struct {
+	mu     sync.Mutex
+	tokens map[string]int
+}
+
+func (l *fakeLimiter) CheckLimit(context.Context, entity.UsageScope) (bool, error) { return true, nil }
+
+func (l *fakeLimiter) Increment(_ context.Context, scope entity.UsageScope, tokens int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens[scope.UserID] += tokens
+	return nil
+}
+
+type noJudge struct{}
+
+func (noJudge) IsMatch(context.Context, string, string) bool { return false }
+
+type noExtractor struct{}
+
+func (noExtractor) ExtractMetadata(context.Context, string) map[string]string { return nil }
+
+func TestExecuteCacheHitAndMiss(t *testing.T) {
+	vs, err := store.NewMemoryStore("")
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	provider := &fakeProvider{}
+	limiter := &fakeLimiter{tokens: make(map[string]int)}
+	u := usecase.NewOrchestrator(vs, limiter, provider, fakeEmbedder{}, noJudge{}, noExtractor{})
+	ctx := context.Background()
+
+	first, err := u.Execute(ctx, entity.AIRequest{UserID: "alice", Prompt: "what is the transfer limit"})
+	if err != nil {
+		t.Fatalf("first Execute: %v", err)
+	}
+	if first.Cached || provider.calls.Load() != 1 {
+		t.Fatalf("first request: cached = %v, provider calls = %d, want a generated answer", first.Cached, provider.calls.Load())
+	}
+	waitForEntries(t, vs, 1)
+
+	tests := []struct {
+		name       string
+		req        entity.AIRequest
+		wantCached bool
+	}{
+		{"same prompt is a hit", entity.AIRequest{UserID: "alice", Prompt: "what is the transfer limit"}, true},
+		{"another user misses", entity.AIRequest{UserID: "bob", Prompt: "what is the transfer limit"}, false},
+		{"another tenant misses", entity.AIRequest{UserID: "alice", TenantID: "acme", Prompt: "what is the transfer limit"}, false},
+		{"lexical guard rejects other numbers", entity.AIRequest{UserID: "alice", Prompt: "what is the transfer limit for 500"}, false},
+		{"no-cache skips the lookup", entity.AIRequest{UserID: "alice", Prompt: "what is the transfer limit", Cache: entity.CacheControl{NoCache: true}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := provider.calls.Load()
+			resp, err := u.Execute(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if resp.Cached != tt.wantCached {
+				t.Errorf("cached = %v, want %v", resp.Cached, tt.wantCached)
+			}
+			if tt.wantCached && resp.ID != first.ID {
+				t.Errorf("hit served entry %s, want %s", resp.ID, first.ID)
+			}
+			wantCalls := int32(1)
+			if tt.wantCached {
+				wantCalls = 0
+			}
+			if got := provider.calls.Load() - before; got != wantCalls {
+				t.Errorf("provider calls = %d, want %d", got, wantCalls)
+			}
+		})
+	}
+}
+
+// --- Private Helpers ---
+
+// waitForEntries waits for the asynchronous cache writes to land
+func waitForEntries(t *testing.T, vs *store.MemoryStore, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		page, err := vs.List(context.Background(), entity.CacheFilter{}, n+1, "")
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(page.Entries) >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cache holds %d entries, want %d", len(page.Entries), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"maps"
+	"regexp"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const deniedText = "[REDACTED]"
+
+// Output rule violations per rule name, published on the admin /metrics endpoint
+var policyViolations = expvar.NewMap("policy_violations")
+
+// OutputPolicy checks generated answers before they are served or cached. Rules run in
+// order, so a redacting rule sees the output of the rules before it; a response that
+// breaks any rule is never written to the semantic cache.
+type OutputPolicy struct {
+	rules []outputRule
+}
+
+// outputRule is an entity.OutputRule compiled for matching
+type outputRule struct {
+	entity.OutputRule
+	patterns []*regexp.Regexp
+	masker   *Redactor // pii rules
+}
+
+// NewOutputPolicy validates and compiles the rules. PII rules use the given detectors.
+func NewOutputPolicy(rules []entity.OutputRule, detectors ...repository.PIIDetector) (*OutputPolicy, error) {
+	p := &OutputPolicy{rules: make([]outputRule, 0, len(rules))}
+	seen := make(map[string]bool)
+	for i, r := range rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("%s_%d", r.Kind, i+1)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("output rule %q: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+
+		compiled, err := compileOutputRule(r, detectors)
+		if err != nil {
+			return nil, fmt.Errorf("output rule %q: %w", r.Name, err)
+		}
+		p.rules = append(p.rules, compiled)
+	}
+	return p, nil
+}
+
+// Apply checks resp against every rule, fixing its content for redacting rules.
+// intent is the extracted metadata of the prompt. It returns what was violated and
+// a *entity.PolicyError when a blocking rule was among them.
+func (p *OutputPolicy) Apply(resp *entity.AIResponse, intent map[string]string) ([]entity.PolicyViolation, error) {
+	var violations []entity.PolicyViolation
+	blocked := false
+	for _, r := range p.rules {
+		detail, fixed, violated := r.check(resp.Content, intent)
+		if !violated {
+			continue
+		}
+		if r.Action == entity.PolicyRedact {
+			resp.Content = fixed
+		}
+		blocked = blocked || r.Action == entity.PolicyBlock
+		policyViolations.Add(r.Name, 1)
+		violations = append(violations, entity.PolicyViolation{Rule: r.Name, Kind: string(r.Kind), Action: r.Action, Detail: detail})
+	}
+	if blocked {
+		return violations, &entity.PolicyError{Violations: violations}
+	}
+	return violations, nil
+}
+
+// enforcePolicy runs the output policy on a response and reports violations in its
+// metadata. It returns whether the response is clean enough to be cached.
+func (u *Orchestrator) enforcePolicy(resp *entity.AIResponse, intent map[string]string) (bool, error) {
+	if u.outputPolicy == nil {
+		return true, nil
+	}
+	violations, err := u.outputPolicy.Apply(resp, intent)
+	if err != nil {
+		log.Printf("[POLICY] Blocked response %s: %v", resp.ID, err)
+		return false, err
+	}
+	if len(violations) == 0 {
+		return true, nil
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]any)
+	}
+	resp.Metadata["policy_violations"] = violations
+	return false, nil
+}
+
+// --- Private Helpers ---
+
+func compileOutputRule(r entity.OutputRule, detectors []repository.PIIDetector) (outputRule, error) {
+	switch r.Action {
+	case entity.PolicyBlock, entity.PolicyRedact, entity.PolicyAnnotate:
+	default:
+		return outputRule{}, fmt.Errorf("unknown action %q", r.Action)
+	}
+
+	compiled := outputRule{OutputRule: r}
+	switch r.Kind {
+	case entity.RuleDenyPattern:
+		if len(r.Patterns) == 0 {
+			return outputRule{}, errors.New("deny_pattern needs at least one pattern")
+		}
+		for _, pattern := range r.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return outputRule{}, fmt.Errorf("invalid pattern: %w", err)
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+	case entity.RulePIILeakage:
+		if len(detectors) == 0 {
+			return outputRule{}, errors.New("pii needs PII detectors")
+		}
+		filtered := detectors
+		if len(r.PIITypes) > 0 {
+			filtered = make([]repository.PIIDetector, len(detectors))
+			for i, d := range detectors {
+				filtered[i] = typeFilter{detector: d, types: r.PIITypes}
+			}
+		}
+		compiled.masker = NewRedactor(filtered...)
+	case entity.RuleMaxLength:
+		if r.MaxLength <= 0 {
+			return outputRule{}, errors.New("max_length must be positive")
+		}
+	case entity.RuleDisclaimer:
+		if strings.TrimSpace(r.Disclaimer) == "" || len(r.Intents) == 0 {
+			return outputRule{}, errors.New("disclaimer needs a disclaimer text and intents")
+		}
+	default:
+		return outputRule{}, fmt.Errorf("unknown kind %q", r.Kind)
+	}
+	return compiled, nil
+}
+
+// check reports whether content violates the rule, with a short detail and the
+// content as a redacting rule would leave it.
+func (r outputRule) check(content string, intent map[string]string) (detail, fixed string, violated bool) {
+	switch r.Kind {
+	case entity.RuleDenyPattern:
+		fixed = content
+		var hits []string
+		for _, re := range r.patterns {
+			if re.MatchString(fixed) {
+				hits = append(hits, re.String())
+				fixed = re.ReplaceAllString(fixed, deniedText)
+			}
+		}
+		return strings.Join(hits, ", "), fixed, len(hits) > 0
+
+	case entity.RulePIILeakage:
+		redaction := r.masker.Redact(content)
+		if !redaction.Found() {
+			return "", content, false
+		}
+		types := slices.Sorted(maps.Keys(redaction.Counts))
+		found := make([]string, len(types))
+		for i, t := range types {
+			found[i] = fmt.Sprintf("%s=%d", t, redaction.Counts[t])
+		}
+		return strings.Join(found, ", "), redaction.Text, true
+
+	case entity.RuleMaxLength:
+		n := utf8.RuneCountInString(content)
+		if n <= r.MaxLength {
+			return "", content, false
+		}
+		return fmt.Sprintf("%d > %d characters", n, r.MaxLength), string([]rune(content)[:r.MaxLength]), true
+
+	case entity.RuleDisclaimer:
+		if !slices.ContainsFunc(r.Intents, func(i string) bool { return strings.EqualFold(i, intent["action"]) }) {
+			return "", content, false
+		}
+		if strings.Contains(strings.ToLower(content), strings.ToLower(r.Disclaimer)) {
+			return "", content, false
+		}
+		return "missing for intent " + intent["action"], strings.TrimRight(content, "\n") + "\n\n" + r.Disclaimer, true
+	}
+	return "", content, false
+}
+
+// typeFilter narrows a detector to the PII types a rule cares about
+type typeFilter struct {
+	detector repository.PIIDetector
+	types    []entity.PIIType
+}
+
+func (f typeFilter) Detect(text string) []entity.PIIMatch {
+	return slices.DeleteFunc(f.detector.Detect(text), func(m entity.PIIMatch) bool {
+		return !slices.Contains(f.types, m.Type)
+	})
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"cmp"
+	"expvar"
+	"maps"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"slices"
+	"strings"
+)
+
+// Redaction metrics, published on the admin /metrics endpoint
+var (
+	piiDetections       = expvar.NewMap("pii_detections") // per PII type
+	piiRedactedRequests = expvar.NewInt("pii_redacted_requests")
+)
+
+// Redactor replaces personal data in prompts with stable placeholders ([EMAIL_1]) so it
+// never reaches the extractor, embedder, judge, provider or the cache. In reversible mode
+// it uses typed tokens (<EMAIL_1>) and keeps the mapping so answers can be re-hydrated.
+type Redactor struct {
+	detectors  []repository.PIIDetector // Earlier detectors win overlapping matches
+	reversible bool
+}
+
+func NewRedactor(detectors ...repository.PIIDetector) *Redactor {
+	return &Redactor{detectors: detectors}
+}
+
+// NewTokenizer is a reversible Redactor: the provider and the cache only see tokens,
+// the caller gets its own values back in the answer.
+func NewTokenizer(detectors ...repository.PIIDetector) *Redactor {
+	return &Redactor{detectors: detectors, reversible: true}
+}
+
+// Redact masks every detection; repeated values share a placeholder.
+func (r *Redactor) Redact(text string) entity.Redaction {
+	matches := r.scan(text)
+	redaction := entity.Redaction{Text: text, Counts: map[entity.PIIType]int{}}
+	if len(matches) == 0 {
+		return redaction
+	}
+	if r.reversible {
+		redaction.Tokens = make(map[string]string)
+	}
+
+	seen := make(map[entity.PIIType]map[string]string)
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		value := text[m.Start:m.End]
+		if seen[m.Type] == nil {
+			seen[m.Type] = make(map[string]string)
+		}
+		placeholder, ok := seen[m.Type][value]
+		if !ok {
+			n := len(seen[m.Type]) + 1
+			if r.reversible {
+				placeholder = entity.Token(m.Type, n)
+				redaction.Tokens[placeholder] = value
+			} else {
+				placeholder = entity.Placeholder(m.Type, n)
+			}
+			seen[m.Type][value] = placeholder
+		}
+
+		b.WriteString(text[last:m.Start])
+		b.WriteString(placeholder)
+		last = m.End
+		redaction.Counts[m.Type]++
+	}
+	b.WriteString(text[last:])
+	redaction.Text = b.String()
+	return redaction
+}
+
+// scan runs every detector and keeps non-overlapping matches in text order.
+// On overlap the longer match wins, then the detector listed first.
+func (r *Redactor) scan(text string) []entity.PIIMatch {
+	type ranked struct {
+		entity.PIIMatch
+		priority int
+	}
+	var all []ranked
+	for i, d := range r.detectors {
+		for _, m := range d.Detect(text) {
+			all = append(all, ranked{m, i})
+		}
+	}
+	slices.SortFunc(all, func(a, b ranked) int {
+		return cmp.Or(cmp.Compare(b.End-b.Start, a.End-a.Start), cmp.Compare(a.priority, b.priority), cmp.Compare(a.Start, b.Start))
+	})
+
+	var kept []entity.PIIMatch
+	for _, m := range all {
+		overlaps := slices.ContainsFunc(kept, func(k entity.PIIMatch) bool {
+			return m.Start < k.End && k.Start < m.End
+		})
+		if !overlaps {
+			kept = append(kept, m.PIIMatch)
+		}
+	}
+	slices.SortFunc(kept, func(a, b entity.PIIMatch) int { return cmp.Compare(a.Start, b.Start) })
+	return kept
+}
+
+// redact swaps the request prompt for its redacted form before anything else sees it.
+// With PII redaction off for the tenant, only requests a rule forces redaction on are redacted.
+func (u *Orchestrator) redact(req *entity.AIRequest) entity.Redaction {
+	redactor := u.redactorFor(*req)
+	if redactor == nil && req.Policy.ForceRedaction {
+		redactor = u.ruleRedactor
+	}
+	if redactor == nil {
+		return entity.Redaction{Text: req.Prompt}
+	}
+	redaction := redactor.Redact(req.Prompt)
+	req.Prompt = redaction.Text
+
+	if redaction.Found() {
+		piiRedactedRequests.Add(1)
+		for t, n := range redaction.Counts {
+			piiDetections.Add(string(t), int64(n))
+		}
+	}
+	return redaction
+}
+
+// redactorFor applies the tenant's redaction policy over the gateway's.
+func (u *Orchestrator) redactorFor(req entity.AIRequest) *Redactor {
+	switch req.Tenant.Redaction {
+	case entity.RedactionOff:
+		return nil
+	case entity.RedactionMask:
+		return cmp.Or(u.masker, u.redactor)
+	case entity.RedactionTokenize:
+		return cmp.Or(u.tokener, u.redactor)
+	}
+	return u.redactor
+}
+
+// rehydrate returns the caller's copy of a response with its own values restored.
+// The original keeps the tokens, so the cached answer stays safe to share.
+func rehydrate(resp *entity.AIResponse, redaction entity.Redaction) *entity.AIResponse {
+	if len(redaction.Tokens) == 0 {
+		return resp
+	}
+	out := *resp
+	out.Metadata = maps.Clone(resp.Metadata)
+	out.Content = redaction.Rehydrate(resp.Content)
+	return &out
+}
+
+// annotateRedaction reports what was redacted in the response metadata.
+func annotateRedaction(resp *entity.AIResponse, redaction entity.Redaction) {
+	if !redaction.Found() {
+		return
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]any)
+	}
+	resp.Metadata["pii_redacted"] = redaction.Counts
+	if len(redaction.Tokens) > 0 {
+		resp.Metadata["pii_rehydrated"] = true
+	}
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sentinel-core/internal/domain/repository"
+	"time"
+)
+
+// Reencryptor re-seals cache entries left behind by a key rotation (or cached before
+// encryption was enabled), periodically and whenever triggered. Passes never overlap.
+type Reencryptor struct {
+	target  repository.Reencrypter
+	trigger chan struct{}
+}
+
+func NewReencryptor(target repository.Reencrypter) *Reencryptor {
+	return &Reencryptor{target: target, trigger: make(chan struct{}, 1)}
+}
+
+// Trigger asks for a pass soon; a pass already pending absorbs the request.
+func (r *Reencryptor) Trigger() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs a pass every interval (never when zero) and on every Trigger until ctx is cancelled.
+func (r *Reencryptor) Start(ctx context.Context, interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		tick = ticker.C
+		go func() {
+			<-ctx.Done()
+			ticker.Stop()
+		}()
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+			case <-r.trigger:
+			}
+			r.pass(ctx)
+		}
+	}()
+}
+
+// --- Private Helpers ---
+
+func (r *Reencryptor) pass(ctx context.Context) {
+	summary, err := r.target.Reencrypt(ctx)
+	switch {
+	case err != nil:
+		log.Printf("[ENCRYPTION] Re-encryption pass failed after %d entries: %v", summary.Scanned, err)
+	case summary.Reencrypted > 0 || summary.Failed > 0:
+		log.Printf("[ENCRYPTION] Re-encrypted %d of %d entries (%d undecryptable)", summary.Reencrypted, summary.Scanned, summary.Failed)
+	}
+}
@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"sentinel-core/internal/domain/entity"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Matches per rule name, published on the admin /metrics endpoint
+var ruleMatches = expvar.NewMap("rule_matches")
+
+// ruleMetadataKeys are the extracted metadata keys rules may match on
+var ruleMetadataKeys = []string{"action", "source", "target"}
+
+// RuleEngine evaluates the declarative request rules loaded from a YAML or JSON file.
+// A reload swaps the whole rule set atomically; a file that fails validation leaves the
+// previous rules in place.
+type RuleEngine struct {
+	path   string
+	models []string // Models force_model may name
+
+	rules atomic.Pointer[[]compiledRule]
+
+	mu      sync.Mutex // Serializes reloads
+	modTime time.Time
+}
+
+// compiledRule is an entity.RequestRule validated and ready to match
+type compiledRule struct {
+	entity.RequestRule
+	prompts []*regexp.Regexp
+	ttl     time.Duration
+}
+
+func NewRuleEngine(path string, models []string) (*RuleEngine, error) {
+	e := &RuleEngine{path: path, models: models}
+	if _, err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload reads and validates the rules file, replacing the active rules on success.
+// It returns the number of rules loaded.
+func (e *RuleEngine) Reload() (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	raw, err := os.ReadFile(e.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	set, err := ParseRules(raw)
+	if err != nil {
+		return 0, err
+	}
+	rules, err := e.compile(set)
+	if err != nil {
+		return 0, err
+	}
+
+	e.rules.Store(&rules)
+	e.modTime = info.ModTime()
+	log.Printf("[RULES] Loaded %d rules from %s", len(rules), e.path)
+	return len(rules), nil
+}
+
+// Watch reloads the rules whenever the file changes, checking every interval until ctx is cancelled.
+func (e *RuleEngine) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !e.changed() {
+					continue
+				}
+				if _, err := e.Reload(); err != nil {
+					log.Printf("[RULES] Reload failed, keeping previous rules: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Rules returns the active rule definitions.
+func (e *RuleEngine) Rules() []entity.RequestRule {
+	active := *e.rules.Load()
+	rules := make([]entity.RequestRule, len(active))
+	for i, r := range active {
+		rules[i] = r.RequestRule
+	}
+	return rules
+}
+
+// Evaluate runs the active rules against a request. With final false the decision is
+// provisional (it will be re-evaluated) and only counted in the metrics if it stops the request.
+func (e *RuleEngine) Evaluate(in entity.RuleInput, final bool) entity.RuleDecision {
+	decision := evaluateRules(*e.rules.Load(), in)
+	if final || decision.Stops() {
+		for _, name := range decision.Matched {
+			ruleMatches.Add(name, 1)
+		}
+	}
+	return decision
+}
+
+// DryRun evaluates an input without side effects, against candidate rules when given
+// (validated exactly like a reload) or else against the active ones.
+func (e *RuleEngine) DryRun(in entity.RuleInput, candidate *entity.RequestRuleSet) (entity.RuleDecision, error) {
+	if candidate == nil {
+		return evaluateRules(*e.rules.Load(), in), nil
+	}
+	rules, err := e.compile(*candidate)
+	if err != nil {
+		return entity.RuleDecision{}, err
+	}
+	return evaluateRules(rules, in), nil
+}
+
+// ParseRules decodes a rules file. JSON is read as YAML; unknown fields are rejected so
+// a typo cannot silently disable a condition.
+func ParseRules(raw []byte) (entity.RequestRuleSet, error) {
+	var set entity.RequestRuleSet
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(&set); err != nil && !errors.Is(err, io.EOF) {
+		return entity.RequestRuleSet{}, fmt.Errorf("%w: rules file: %v", entity.ErrInvalidRequest, err)
+	}
+	return set, nil
+}
+
+// applyRules evaluates the rules for a request and applies their effects to it. It runs
+// before any outbound call (no metadata yet, final false) and again with the extracted
+// intent; a request is counted and logged once, by the pass that decides it. Prompt
+// patterns always see the caller's original prompt.
+func (u *Orchestrator) applyRules(req *entity.AIRequest, prompt string, intent map[string]string, final bool) error {
+	if u.rules == nil {
+		return nil
+	}
+	decision := u.rules.Evaluate(entity.RuleInput{
+		UserID:   req.UserID,
+		TenantID: req.TenantID,
+		Route:    req.Route,
+		Model:    req.Model,
+		Prompt:   prompt,
+		Metadata: intent,
+	}, final)
+	req.Policy = decision
+
+	// A stopping decision is final whichever pass makes it, so this logs once per request
+	switch {
+	case decision.Deny:
+		log.Printf("[RULES] Denied request from %s (%s)", req.UserID, strings.Join(decision.Matched, ","))
+		return fmt.Errorf("%w: %s", entity.ErrRequestDenied, decision.Reason)
+	case decision.RequireApproval:
+		return fmt.Errorf("%w (%s)", entity.ErrApprovalRequired, strings.Join(decision.Matched, ","))
+	}
+	if decision.DisableCache {
+		req.Cache.NoCache = true
+		req.Cache.NoStore = true
+	}
+	return nil
+}
+
+// annotateRules reports the matched rules in the response metadata.
+func annotateRules(resp *entity.AIResponse, decision entity.RuleDecision) {
+	if len(decision.Matched) == 0 {
+		return
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]any)
+	}
+	resp.Metadata["rules_matched"] = decision.Matched
+}
+
+// --- Private Helpers ---
+
+func (e *RuleEngine) changed() bool {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !info.ModTime().Equal(e.modTime)
+}
+
+// compile validates a rule set, reporting every problem rather than the first
+func (e *RuleEngine) compile(set entity.RequestRuleSet) ([]compiledRule, error) {
+	var problems []string
+	seen := make(map[string]bool)
+	rules := make([]compiledRule, 0, len(set.Rules))
+	for i, r := range set.Rules {
+		if r.Name == "" {
+			problems = append(problems, fmt.Sprintf("rule %d: missing name", i+1))
+			continue
+		}
+		if seen[r.Name] {
+			problems = append(problems, fmt.Sprintf("rule %q: duplicate name", r.Name))
+		}
+		seen[r.Name] = true
+
+		compiled, errs := e.compileRule(r)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("rule %q: %v", r.Name, err))
+		}
+		rules = append(rules, compiled)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidRequest, strings.Join(problems, "; "))
+	}
+	return rules, nil
+}
+
+func (e *RuleEngine) compileRule(r entity.RequestRule) (compiledRule, []error) {
+	var errs []error
+	compiled := compiledRule{RequestRule: r}
+
+	// Conditions
+	for _, globs := range [][]string{r.Match.Users, r.Match.Tenants, r.Match.Routes, r.Match.Models} {
+		for _, g := range globs {
+			if _, err := path.Match(g, ""); err != nil {
+				errs = append(errs, fmt.Errorf("invalid pattern %q", g))
+			}
+		}
+	}
+	for key := range r.Match.Metadata {
+		if !slices.Contains(ruleMetadataKeys, key) {
+			errs = append(errs, fmt.Errorf("unknown metadata key %q (want one of %s)", key, strings.Join(ruleMetadataKeys, ", ")))
+		}
+	}
+	for _, p := range r.Match.PromptPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid prompt pattern: %w", err))
+			continue
+		}
+		compiled.prompts = append(compiled.prompts, re)
+	}
+
+	// Effects
+	then := r.Then
+	if then.ForceModel != "" && !slices.Contains(e.models, then.ForceModel) {
+		errs = append(errs, fmt.Errorf("force_model %q is not a configured model (%s)", then.ForceModel, strings.Join(e.models, ", ")))
+	}
+	if then.TTL != "" {
+		ttl, err := time.ParseDuration(then.TTL)
+		if err != nil || ttl <= 0 {
+			errs = append(errs, fmt.Errorf("invalid ttl %q", then.TTL))
+		}
+		compiled.ttl = ttl
+	}
+	if then == (entity.RuleEffects{}) && !r.Final {
+		errs = append(errs, errors.New("no effect"))
+	}
+	return compiled, errs
+}
+
+// evaluateRules merges the effects of the matching rules in order
+func evaluateRules(rules []compiledRule, in entity.RuleInput) entity.RuleDecision {
+	var d entity.RuleDecision
+	for _, r := range rules {
+		if !r.matches(in) {
+			continue
+		}
+		d.Matched = append(d.Matched, r.Name)
+
+		then := r.Then
+		if then.Deny && !d.Deny {
+			d.Deny = true
+			d.Reason = cmp.Or(then.Reason, "rule "+r.Name)
+		}
+		d.RequireApproval = d.RequireApproval || then.RequireApproval
+		d.DisableCache = d.DisableCache || then.DisableCache
+		d.ForceRedaction = d.ForceRedaction || then.ForceRedaction
+		if d.ForceModel == "" {
+			d.ForceModel = then.ForceModel
+		}
+		if d.TTLSeconds == 0 {
+			d.TTLSeconds = int64(r.ttl.Seconds())
+		}
+		if r.Final {
+			break
+		}
+	}
+	return d
+}
+
+func (r compiledRule) matches(in entity.RuleInput) bool {
+	m := r.Match
+	if !matchesGlob(m.Users, in.UserID) || !matchesGlob(m.Tenants, in.TenantID) ||
+		!matchesGlob(m.Routes, in.Route) || !matchesGlob(m.Models, in.Model) {
+		return false
+	}
+	for key, values := range m.Metadata {
+		// Before extraction there is no metadata to match
+		got, ok := in.Metadata[key]
+		if !ok || !slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, got) }) {
+			return false
+		}
+	}
+	if len(r.prompts) > 0 && !slices.ContainsFunc(r.prompts, func(re *regexp.Regexp) bool { return re.MatchString(in.Prompt) }) {
+		return false
+	}
+	return true
+}
+
+func matchesGlob(globs []string, value string) bool {
+	if len(globs) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(globs, func(g string) bool {
+		ok, _ := path.Match(g, value)
+		return ok
+	})
+}
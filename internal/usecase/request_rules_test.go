@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"errors"
+	"expvar"
+	"reflect"
+	"sentinel-core/internal/domain/entity"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testRuleModels = []string{"gemini-2.5-flash", "gemini-2.5-flash-lite"}
+
+func mustCompile(t *testing.T, rules ...entity.RequestRule) []compiledRule {
+	t.Helper()
+	e := &RuleEngine{models: testRuleModels}
+	compiled, err := e.compile(entity.RequestRuleSet{Rules: rules})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	return compiled
+}
+
+func TestEvaluateRules(t *testing.T) {
+	rules := mustCompile(t,
+		entity.RequestRule{Name: "ops-bypass", Match: entity.RuleConditions{Users: []string{"ops-*"}}, Final: true},
+		entity.RequestRule{
+			Name:  "approve-transfers",
+			Match: entity.RuleConditions{Metadata: map[string][]string{"action": {"transfer"}}},
+			Then:  entity.RuleEffects{RequireApproval: true},
+		},
+		entity.RequestRule{
+			Name:  "no-secrets",
+			Match: entity.RuleConditions{PromptPatterns: []string{`(?i)\bpassword\b`}},
+			Then:  entity.RuleEffects{Deny: true, Reason: "no credentials"},
+		},
+		entity.RequestRule{
+			Name:  "regulated",
+			Match: entity.RuleConditions{Tenants: []string{"bank-*"}},
+			Then:  entity.RuleEffects{ForceRedaction: true, TTL: "1h", ForceModel: "gemini-2.5-flash"},
+		},
+		entity.RequestRule{
+			Name:  "trial",
+			Match: entity.RuleConditions{Users: []string{"trial-*"}, Routes: []string{"/v1/chat"}},
+			Then:  entity.RuleEffects{ForceModel: "gemini-2.5-flash-lite", TTL: "5m"},
+		},
+	)
+
+	tests := []struct {
+		name string
+		in   entity.RuleInput
+		want entity.RuleDecision
+	}{
+		{
+			name: "no match",
+			in:   entity.RuleInput{UserID: "alice", TenantID: "default", Route: "/v1/chat", Prompt: "hello"},
+			want: entity.RuleDecision{},
+		},
+		{
+			name: "final rule stops evaluation",
+			in:   entity.RuleInput{UserID: "ops-1", Prompt: "my password"},
+			want: entity.RuleDecision{Matched: []string{"ops-bypass"}},
+		},
+		{
+			name: "deny with reason",
+			in:   entity.RuleInput{UserID: "alice", Prompt: "reset my Password"},
+			want: entity.RuleDecision{Matched: []string{"no-secrets"}, Deny: true, Reason: "no credentials"},
+		},
+		{
+			name: "metadata never matches before extraction",
+			in:   entity.RuleInput{UserID: "alice", Prompt: "send money"},
+			want: entity.RuleDecision{},
+		},
+		{
+			name: "metadata matches case-insensitively",
+			in:   entity.RuleInput{UserID: "alice", Metadata: map[string]string{"action": "Transfer"}},
+			want: entity.RuleDecision{Matched: []string{"approve-transfers"}, RequireApproval: true},
+		},
+		{
+			name: "first rule setting model and ttl wins",
+			in:   entity.RuleInput{UserID: "trial-7", TenantID: "bank-klang", Route: "/v1/chat"},
+			want: entity.RuleDecision{
+				Matched:        []string{"regulated", "trial"},
+				ForceRedaction: true,
+				ForceModel:     "gemini-2.5-flash",
+				TTLSeconds:     3600,
+			},
+		},
+		{
+			name: "route glob must match too",
+			in:   entity.RuleInput{UserID: "trial-7", Route: "/v2/chat"},
+			want: entity.RuleDecision{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRules(rules, tt.in)
+			if !slices.Equal(got.Matched, tt.want.Matched) {
+				t.Errorf("matched = %v, want %v", got.Matched, tt.want.Matched)
+			}
+			got.Matched, tt.want.Matched = nil, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decision = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRuleValidation(t *testing.T) {
+	e := &RuleEngine{models: testRuleModels}
+	tests := []struct {
+		name    string
+		rule    entity.RequestRule
+		wantErr string // Substring of the only expected problem, empty for a valid rule
+	}{
+		{"valid", entity.RequestRule{Name: "ok", Then: entity.RuleEffects{DisableCache: true}}, ""},
+		{"final without effects", entity.RequestRule{Name: "stop", Final: true}, ""},
+		{"no effect", entity.RequestRule{Name: "noop"}, "no effect"},
+		{"bad glob", entity.RequestRule{Name: "g", Match: entity.RuleConditions{Users: []string{"[a-"}}, Then: entity.RuleEffects{Deny: true}}, "invalid pattern"},
+		{"bad regexp", entity.RequestRule{Name: "r", Match: entity.RuleConditions{PromptPatterns: []string{"(unclosed"}}, Then: entity.RuleEffects{Deny: true}}, "invalid prompt pattern"},
+		{"unknown metadata key", entity.RequestRule{Name: "m", Match: entity.RuleConditions{Metadata: map[string][]string{"amount": {"1"}}}, Then: entity.RuleEffects{Deny: true}}, "unknown metadata key"},
+		{"unknown model", entity.RequestRule{Name: "f", Then: entity.RuleEffects{ForceModel: "gpt-5"}}, "is not a configured model"},
+		{"bad ttl", entity.RequestRule{Name: "t", Then: entity.RuleEffects{TTL: "soon"}}, "invalid ttl"},
+		{"negative ttl", entity.RequestRule{Name: "t", Then: entity.RuleEffects{TTL: "-1m"}}, "invalid ttl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := e.compileRule(tt.rule)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Fatalf("unexpected problems: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Fatalf("problems = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompileReportsEveryProblem(t *testing.T) {
+	e := &RuleEngine{models: testRuleModels}
+	_, err := e.compile(entity.RequestRuleSet{Rules: []entity.RequestRule{
+		{Then: entity.RuleEffects{Deny: true}},
+		{Name: "dup", Then: entity.RuleEffects{Deny: true}},
+		{Name: "dup", Then: entity.RuleEffects{TTL: "x"}},
+	}})
+	if !errors.Is(err, entity.ErrInvalidRequest) {
+		t.Fatalf("err = %v, want ErrInvalidRequest", err)
+	}
+	for _, want := range []string{"rule 1: missing name", `rule "dup": duplicate name`, `rule "dup": invalid ttl`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	e := &RuleEngine{models: testRuleModels}
+	active := mustCompile(t, entity.RequestRule{
+		Name:  "deny-all",
+		Match: entity.RuleConditions{Users: []string{"*"}},
+		Then:  entity.RuleEffects{Deny: true},
+	})
+	e.rules.Store(&active)
+	in := entity.RuleInput{UserID: "alice", Prompt: "hi"}
+
+	tests := []struct {
+		name        string
+		candidate   *entity.RequestRuleSet
+		wantMatched []string
+		wantErr     bool
+	}{
+		{"active rules", nil, []string{"deny-all"}, false},
+		{"candidate rules", &entity.RequestRuleSet{Rules: []entity.RequestRule{
+			{Name: "cache-off", Match: entity.RuleConditions{Users: []string{"ali*"}}, Then: entity.RuleEffects{DisableCache: true}},
+		}}, []string{"cache-off"}, false},
+		{"invalid candidate", &entity.RequestRuleSet{Rules: []entity.RequestRule{{Name: "noop"}}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := e.DryRun(in, tt.candidate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got.Matched, tt.wantMatched) {
+				t.Errorf("matched = %v, want %v", got.Matched, tt.wantMatched)
+			}
+		})
+	}
+
+	// A dry run never replaces the active rules
+	if rules := e.Rules(); len(rules) != 1 || rules[0].Name != "deny-all" {
+		t.Errorf("active rules changed: %+v", rules)
+	}
+}
+
+func TestEvaluateCountsOncePerRequest(t *testing.T) {
+	e := &RuleEngine{models: testRuleModels}
+	active := mustCompile(t,
+		entity.RequestRule{Name: "count-cache-off", Match: entity.RuleConditions{Users: []string{"counted"}}, Then: entity.RuleEffects{DisableCache: true}},
+		entity.RequestRule{Name: "count-deny", Match: entity.RuleConditions{Users: []string{"denied"}}, Then: entity.RuleEffects{Deny: true}},
+	)
+	e.rules.Store(&active)
+	matches := func(rule string) int64 {
+		if v, ok := ruleMatches.Get(rule).(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+
+	// A provisional pass is only counted when it stops the request
+	e.Evaluate(entity.RuleInput{UserID: "counted"}, false)
+	e.Evaluate(entity.RuleInput{UserID: "counted"}, true)
+	if got := matches("count-cache-off"); got != 1 {
+		t.Errorf("count-cache-off counted %d times, want 1", got)
+	}
+	e.Evaluate(entity.RuleInput{UserID: "denied"}, false)
+	if got := matches("count-deny"); got != 1 {
+		t.Errorf("count-deny counted %d times, want 1", got)
+	}
+}
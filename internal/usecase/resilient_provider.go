@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"log"
 	"math/rand"
 	"sentinel-core/internal/domain/entity"
 	"sentinel-core/internal/domain/repository"
@@ -12,16 +13,21 @@ import (
 
 type ResilientProvider struct {
 	primary    repository.AIProvider
-	fallback   repository.AIProvider // The "Plan B" (e.g., Gemini Flash)
+	fallbacks  []repository.AIProvider // The "Plan B" (e.g., Gemini Flash), then C...
 	maxRetries int
 	baseDelay  time.Duration
 	timeout    time.Duration // The Safety Layer Timeout
 }
 
 func NewResilientProvider(primary, fallback repository.AIProvider) *ResilientProvider {
+	return NewResilientChain(primary, fallback)
+}
+
+// NewResilientChain tries the fallbacks in order, once each, after the primary's retries.
+func NewResilientChain(primary repository.AIProvider, fallbacks ...repository.AIProvider) *ResilientProvider {
 	return &ResilientProvider{
 		primary:    primary,
-		fallback:   fallback,
+		fallbacks:  fallbacks,
 		maxRetries: 2, // Total 3 attempts for Primary
 		baseDelay:  500 * time.Millisecond,
 		timeout:    25 * time.Second, // Global cap per generation
@@ -43,8 +49,16 @@ func (r *ResilientProvider) Generate(ctx context.Context, prompt string) (*entit
 	fmt.Printf("[RELIABILITY] Primary exhausted. Switching to FALLBACK. Error: %v\n", err)
 
 	// 3. Tiered Fallback Flow
-	// If primary fails, we try the fallback model ONCE (usually a faster/cheaper model)
-	resp, err = r.fallback.Generate(resCtx, prompt)
+	// If primary fails, we try each fallback model ONCE (usually a faster/cheaper model)
+	for i, fallback := range r.fallbacks {
+		resp, err = fallback.Generate(resCtx, prompt)
+		if err == nil {
+			break
+		}
+		if i < len(r.fallbacks)-1 {
+			log.Printf("[RELIABILITY] Fallback %d failed, trying the next one. Error: %v", i+1, err)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("both primary and fallback failed: %w", err)
 	}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sentinel-core/internal/domain/entity"
+	"time"
+)
+
+// staleWhileRevalidate reports whether stale entries may be served.
+func (u *Orchestrator) staleWhileRevalidate() bool {
+	return u.refreshSlots != nil
+}
+
+// searchWindow is how far back Search may look: the freshness window, plus the
+// staleness allowance when stale-while-revalidate is on.
+func (u *Orchestrator) searchWindow() time.Duration {
+	if u.staleWhileRevalidate() {
+		return u.freshness + u.maxStaleness
+	}
+	return u.freshness
+}
+
+// markStale flags a served hit as past its freshness window.
+func markStale(resp *entity.AIResponse, age time.Duration) {
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]any)
+	}
+	resp.Stale = true
+	resp.Metadata["stale"] = true
+	resp.Metadata["age_seconds"] = int64(age.Seconds())
+}
+
+// scheduleRefresh regenerates a stale entry in the background and overwrites it in place.
+// Refreshes are deduplicated per entry and bounded by the refresh slot pool; when the
+// pool is exhausted the stale entry keeps being served until a later request retries.
+func (u *Orchestrator) scheduleRefresh(req entity.AIRequest, entryID string, vector []float32, meta map[string]string) {
+	if _, busy := u.refreshing.LoadOrStore(entryID, struct{}{}); busy {
+		return
+	}
+
+	select {
+	case u.refreshSlots <- struct{}{}:
+	default:
+		u.refreshing.Delete(entryID)
+		log.Printf("[SWR] Refresh of %s skipped: concurrency limit reached", entryID)
+		return
+	}
+
+	go func() {
+		defer func() {
+			<-u.refreshSlots
+			u.refreshing.Delete(entryID)
+		}()
+
+		resp, err := u.providerFor(req).Generate(context.Background(), req.Prompt)
+		if err != nil {
+			log.Printf("[SWR] Refresh of %s failed: %v", entryID, err)
+			return
+		}
+
+		// Same ID, so the upsert replaces the stale point
+		resp.ID = entryID
+		if clean, err := u.enforcePolicy(resp, meta); err != nil || !clean {
+			log.Printf("[SWR] Refresh of %s discarded: output policy violation", entryID)
+			u.chargeTokens(usageScope(req), resp.TokenCount)
+			return
+		}
+		u.backgroundUpdate(req, resp, vector, meta)
+	}()
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sentinel-core/internal/domain/entity"
+	"sentinel-core/internal/domain/repository"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tenant IDs end up in Redis keys and cache payloads, so they are kept to a safe alphabet
+var tenantIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// TenantService manages tenant configurations and resolves the tenant of each request.
+// Resolved tenants are cached for cacheTTL, so admin changes reach every replica within it.
+type TenantService struct {
+	store    repository.TenantStore
+	models   []string // Models a fallback chain may name
+	cacheTTL time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedTenant
+}
+
+type cachedTenant struct {
+	tenant    entity.Tenant
+	fetchedAt time.Time
+}
+
+func NewTenantService(store repository.TenantStore, models []string, cacheTTL time.Duration) *TenantService {
+	return &TenantService{store: store, models: models, cacheTTL: cacheTTL, cache: make(map[string]cachedTenant)}
+}
+
+// Resolve returns the configuration of a tenant; an empty ID is the default tenant,
+// which exists with the gateway defaults until configured. Other unconfigured tenants
+// are entity.ErrUnknownTenant.
+func (s *TenantService) Resolve(ctx context.Context, id string) (entity.Tenant, error) {
+	if id == "" {
+		id = entity.DefaultTenant
+	}
+	s.mu.RLock()
+	cached, ok := s.cache[id]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < s.cacheTTL {
+		return cached.tenant, nil
+	}
+
+	tenant, err := s.store.Get(ctx, id)
+	switch {
+	case errors.Is(err, entity.ErrResourceNotFound) && id == entity.DefaultTenant:
+		tenant = &entity.Tenant{ID: entity.DefaultTenant}
+	case errors.Is(err, entity.ErrResourceNotFound):
+		return entity.Tenant{}, fmt.Errorf("%w: %s", entity.ErrUnknownTenant, id)
+	case err != nil:
+		return entity.Tenant{}, err
+	}
+
+	s.mu.Lock()
+	s.cache[id] = cachedTenant{tenant: *tenant, fetchedAt: time.Now()}
+	s.mu.Unlock()
+	return *tenant, nil
+}
+
+// Put creates or replaces a tenant's configuration.
+func (s *TenantService) Put(ctx context.Context, tenant entity.Tenant) (entity.Tenant, error) {
+	if err := s.validate(tenant); err != nil {
+		return entity.Tenant{}, err
+	}
+	tenant.UpdatedAt = time.Now().UTC()
+	if err := s.store.Save(ctx, tenant); err != nil {
+		return entity.Tenant{}, err
+	}
+	s.forget(tenant.ID)
+	log.Printf("[TENANTS] Saved tenant %s", tenant.ID)
+	return tenant, nil
+}
+
+func (s *TenantService) Get(ctx context.Context, id string) (*entity.Tenant, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *TenantService) List(ctx context.Context) ([]entity.Tenant, error) {
+	return s.store.List(ctx)
+}
+
+// Delete removes a tenant; its requests are rejected from then on (the default tenant
+// falls back to the gateway defaults). Its cached answers stay until they expire or are purged.
+func (s *TenantService) Delete(ctx context.Context, id string) error {
+	if err := s.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.forget(id)
+	log.Printf("[TENANTS] Deleted tenant %s", id)
+	return nil
+}
+
+// ValidTenantID reports whether id is a well-formed tenant ID.
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// --- Private Helpers ---
+
+func (s *TenantService) forget(id string) {
+	s.mu.Lock()
+	delete(s.cache, id)
+	s.mu.Unlock()
+}
+
+// validate collects every problem of a configuration so an admin can fix them in one go
+func (s *TenantService) validate(t entity.Tenant) error {
+	var problems []string
+	if !ValidTenantID(t.ID) {
+		problems = append(problems, "id must be 1-64 letters, digits, '-' or '_'")
+	}
+	if t.UserTokenBudget < 0 || t.TokenBudget < 0 || t.CacheTTLSeconds < 0 {
+		problems = append(problems, "budgets and cache_ttl_seconds cannot be negative")
+	}
+	switch t.CacheScope {
+	case "", entity.CacheScopeUser, entity.CacheScopeTenant:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown cache_scope %q", t.CacheScope))
+	}
+	switch t.Redaction {
+	case entity.RedactionDefault, entity.RedactionOff, entity.RedactionMask, entity.RedactionTokenize:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown redaction %q", t.Redaction))
+	}
+	for _, p := range t.AllowedModels {
+		if _, err := path.Match(p, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid allowed_models pattern %q", p))
+		}
+	}
+	for _, model := range t.FallbackChain {
+		if !slices.Contains(s.models, model) {
+			problems = append(problems, fmt.Sprintf("fallback_chain model %q is not registered", model))
+		} else if !entity.AllowsModel(t.AllowedModels, model) {
+			problems = append(problems, fmt.Sprintf("fallback_chain model %q is not in allowed_models", model))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", entity.ErrInvalidRequest, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// resolveTenant attaches the tenant configuration to a request; an empty tenant is the default one.
+func (u *Orchestrator) resolveTenant(ctx context.Context, req *entity.AIRequest) error {
+	req.TenantID = cmp.Or(req.TenantID, entity.DefaultTenant)
+	if u.tenants == nil {
+		req.Tenant = entity.Tenant{ID: req.TenantID}
+		return nil
+	}
+	tenant, err := u.tenants.Resolve(ctx, req.TenantID)
+	if err != nil {
+		return err
+	}
+	req.Tenant = tenant
+	return nil
+}
+
+// usageScope is whose budgets a request draws from.
+func usageScope(req entity.AIRequest) entity.UsageScope {
+	return entity.UsageScope{
+		TenantID:     req.TenantID,
+		UserID:       req.UserID,
+		RateTier:     req.RateTier,
+		UserBudget:   req.Tenant.UserTokenBudget,
+		TenantBudget: req.Tenant.TokenBudget,
+	}
+}